@@ -0,0 +1,46 @@
+// Package bot adapts the human-in-the-loop approval workflow to chat
+// platforms (Slack, Discord, MS Teams, ...), in the spirit of BotKube-style
+// bot adapters: one small interface, one implementation per platform.
+package bot
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ApprovalRequest describes an out-of-policy action awaiting a human decision.
+type ApprovalRequest struct {
+	ActionID    string
+	ActionType  string
+	Target      string
+	Reason      string
+	RequestedAt time.Time
+}
+
+// ApprovalResponse is the result of an approver replying to a prompt.
+type ApprovalResponse struct {
+	ActionID string
+	Approver string
+	Approved bool
+}
+
+// Bot delivers an interactive approve/deny prompt for a pending action and
+// surfaces the approver's reply. Implementations are platform-specific
+// (Slack, Discord, Teams); callers should treat failures to post as
+// non-fatal and fall back to another configured Bot.
+type Bot interface {
+	// Name identifies the adapter, e.g. "slack".
+	Name() string
+
+	// PostApprovalPrompt delivers an interactive approve/deny prompt for the action.
+	PostApprovalPrompt(ctx context.Context, req ApprovalRequest) error
+
+	// Responses streams approve/deny replies as approvers act on prompts.
+	// The channel is never closed by the adapter.
+	Responses() <-chan ApprovalResponse
+
+	// HandleCallback processes an inbound webhook/slash-command callback from
+	// the platform and, on a valid approve/deny reply, publishes to Responses.
+	HandleCallback(w http.ResponseWriter, r *http.Request)
+}
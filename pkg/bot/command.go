@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseDecision parses the free-text reply approvers type in chat, e.g.
+// "approve act-123" or "deny act-123 too risky". Extra words after the
+// action ID are ignored.
+func parseDecision(approver, text string) (ApprovalResponse, error) {
+	fields := strings.Fields(strings.TrimSpace(text))
+	if len(fields) < 2 {
+		return ApprovalResponse{}, fmt.Errorf("expected \"approve <action_id>\" or \"deny <action_id>\", got %q", text)
+	}
+
+	var approved bool
+	switch strings.ToLower(fields[0]) {
+	case "approve", "approved", "yes", "lgtm":
+		approved = true
+	case "deny", "deny_action", "no", "reject":
+		approved = false
+	default:
+		return ApprovalResponse{}, fmt.Errorf("unrecognized command %q", fields[0])
+	}
+
+	return ApprovalResponse{
+		ActionID: fields[1],
+		Approver: approver,
+		Approved: approved,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DiscordBot posts approval prompts to a Discord webhook and accepts
+// approver replies via an interaction callback pointed at HandleCallback.
+type DiscordBot struct {
+	webhookURL string
+	httpClient *http.Client
+	responses  chan ApprovalResponse
+}
+
+// NewDiscordBot builds a DiscordBot that posts to the given webhook URL.
+func NewDiscordBot(webhookURL string) *DiscordBot {
+	return &DiscordBot{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{},
+		responses:  make(chan ApprovalResponse, 32),
+	}
+}
+
+func (d *DiscordBot) Name() string { return "discord" }
+
+func (d *DiscordBot) Responses() <-chan ApprovalResponse { return d.responses }
+
+func (d *DiscordBot) PostApprovalPrompt(ctx context.Context, req ApprovalRequest) error {
+	content := fmt.Sprintf("**Action needs approval**: `%s` on `%s`\nReason: %s\nReply with `approve %s` or `deny %s`.",
+		req.ActionType, req.Target, req.Reason, req.ActionID, req.ActionID)
+
+	payload, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return fmt.Errorf("marshal discord payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build discord request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("post to discord: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// HandleCallback handles a Discord interaction callback (JSON body with
+// "member.user.username" and a "content" field carrying the reply text) and
+// pushes a decision onto Responses.
+func (d *DiscordBot) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Content string `json:"content"`
+		Member  struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+		} `json:"member"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	decision, err := parseDecision(body.Member.User.Username, body.Content)
+	if err != nil {
+		logger.Log.Warn("Discord callback could not be parsed", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d.responses <- decision
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SlackBot posts approval prompts to a Slack incoming webhook and accepts
+// approver replies via a Slack slash-command (or interactivity) callback
+// pointed at HandleCallback.
+type SlackBot struct {
+	webhookURL string
+	httpClient *http.Client
+	responses  chan ApprovalResponse
+}
+
+// NewSlackBot builds a SlackBot that posts to the given incoming webhook URL.
+func NewSlackBot(webhookURL string) *SlackBot {
+	return &SlackBot{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{},
+		responses:  make(chan ApprovalResponse, 32),
+	}
+}
+
+func (s *SlackBot) Name() string { return "slack" }
+
+func (s *SlackBot) Responses() <-chan ApprovalResponse { return s.responses }
+
+func (s *SlackBot) PostApprovalPrompt(ctx context.Context, req ApprovalRequest) error {
+	text := fmt.Sprintf(":warning: Action `%s` on `%s` needs approval: %s\nReply with `approve %s` or `deny %s`.",
+		req.ActionType, req.Target, req.Reason, req.ActionID, req.ActionID)
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("marshal slack payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build slack request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("post to slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// HandleCallback handles a Slack slash-command POST (application/x-www-form-urlencoded
+// with "user_name" and "text" fields) and pushes a decision onto Responses.
+func (s *SlackBot) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	approver := r.PostFormValue("user_name")
+	decision, err := parseDecision(approver, r.PostFormValue("text"))
+	if err != nil {
+		logger.Log.Warn("Slack callback could not be parsed", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.responses <- decision
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Recorded."))
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// PendingApproval is a unit of work awaiting a human decision. Payload holds
+// the caller's serialized representation of the underlying action (e.g. a
+// protojson-encoded events.Action) so it can be reconstructed once a
+// decision comes back, without this package needing to know its type.
+type PendingApproval struct {
+	ActionID   string
+	ActionType string
+	Target     string
+	Reason     string
+	CreatedAt  time.Time
+	Payload    []byte
+}
+
+// Store tracks pending approvals with a TTL so forgotten or unanswered
+// prompts eventually expire instead of leaking memory forever.
+type Store struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	pending map[string]PendingApproval
+}
+
+// NewStore builds an in-memory approval Store. Entries older than ttl are
+// dropped lazily on access and by the periodic sweep started by StartSweeper.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		pending: make(map[string]PendingApproval),
+	}
+}
+
+// Put records a pending approval, keyed by action ID.
+func (s *Store) Put(p PendingApproval) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[p.ActionID] = p
+}
+
+// Take removes and returns the pending approval for actionID, if present and
+// not yet expired.
+func (s *Store) Take(actionID string) (PendingApproval, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.pending[actionID]
+	if !ok {
+		return PendingApproval{}, false
+	}
+	delete(s.pending, actionID)
+
+	if s.ttl > 0 && time.Since(p.CreatedAt) > s.ttl {
+		return PendingApproval{}, false
+	}
+	return p, true
+}
+
+// StartSweeper periodically evicts expired entries in the background. It
+// returns a stop function that halts the sweeper.
+func (s *Store) StartSweeper(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.evictExpired()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (s *Store) evictExpired() {
+	if s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-s.ttl)
+	for id, p := range s.pending {
+		if p.CreatedAt.Before(cutoff) {
+			delete(s.pending, id)
+		}
+	}
+}
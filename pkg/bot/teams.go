@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TeamsBot posts approval prompts to an MS Teams incoming webhook and
+// accepts approver replies via a connector action callback pointed at
+// HandleCallback.
+type TeamsBot struct {
+	webhookURL string
+	httpClient *http.Client
+	responses  chan ApprovalResponse
+}
+
+// NewTeamsBot builds a TeamsBot that posts to the given webhook URL.
+func NewTeamsBot(webhookURL string) *TeamsBot {
+	return &TeamsBot{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{},
+		responses:  make(chan ApprovalResponse, 32),
+	}
+}
+
+func (t *TeamsBot) Name() string { return "teams" }
+
+func (t *TeamsBot) Responses() <-chan ApprovalResponse { return t.responses }
+
+func (t *TeamsBot) PostApprovalPrompt(ctx context.Context, req ApprovalRequest) error {
+	text := fmt.Sprintf("Action **%s** on **%s** needs approval: %s  \nReply with `approve %s` or `deny %s`.",
+		req.ActionType, req.Target, req.Reason, req.ActionID, req.ActionID)
+
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("marshal teams payload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build teams request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("post to teams: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("teams webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// HandleCallback handles a Teams connector action callback (JSON body with
+// "approver" and "text" fields) and pushes a decision onto Responses.
+func (t *TeamsBot) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Approver string `json:"approver"`
+		Text     string `json:"text"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	decision, err := parseDecision(body.Approver, body.Text)
+	if err != nil {
+		logger.Log.Warn("Teams callback could not be parsed", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t.responses <- decision
+	w.WriteHeader(http.StatusOK)
+}
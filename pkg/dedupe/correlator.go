@@ -0,0 +1,74 @@
+package dedupe
+
+import (
+	"sync"
+	"time"
+)
+
+// CorrelationGroup is formed once enough distinct alerts for the same
+// service arrive within a window to look like a storm rather than
+// independent pings.
+type CorrelationGroup struct {
+	ServiceName string
+	EventIDs    []string
+	WindowStart time.Time
+	WindowEnd   time.Time
+}
+
+type correlationWindow struct {
+	eventIDByFP map[string]string
+	start       time.Time
+}
+
+// Correlator buckets alerts by service name into fixed windows that open at
+// the first alert seen and reports a CorrelationGroup once threshold
+// distinct fingerprints have landed inside the window.
+type Correlator struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	byService map[string]*correlationWindow
+}
+
+// NewCorrelator builds a Correlator that fires once threshold distinct
+// alerts for the same service arrive within w of the first one seen.
+func NewCorrelator(threshold int, w time.Duration) *Correlator {
+	return &Correlator{
+		threshold: threshold,
+		window:    w,
+		byService: make(map[string]*correlationWindow),
+	}
+}
+
+// Observe records one alert for serviceName at time now. Once threshold
+// distinct fingerprints have been seen for serviceName inside the window,
+// it returns the formed group (and resets that service's window); ok is
+// false otherwise.
+func (c *Correlator) Observe(serviceName, fingerprint, eventID string, now time.Time) (group CorrelationGroup, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	win, exists := c.byService[serviceName]
+	if !exists || now.Sub(win.start) > c.window {
+		win = &correlationWindow{eventIDByFP: make(map[string]string), start: now}
+		c.byService[serviceName] = win
+	}
+
+	win.eventIDByFP[fingerprint] = eventID
+	if len(win.eventIDByFP) < c.threshold {
+		return CorrelationGroup{}, false
+	}
+
+	ids := make([]string, 0, len(win.eventIDByFP))
+	for _, id := range win.eventIDByFP {
+		ids = append(ids, id)
+	}
+	delete(c.byService, serviceName)
+
+	return CorrelationGroup{
+		ServiceName: serviceName,
+		EventIDs:    ids,
+		WindowStart: win.start,
+		WindowEnd:   now,
+	}, true
+}
@@ -0,0 +1,46 @@
+// Package dedupe coalesces bursts of near-identical alerts into a single
+// logical event, and groups distinct alerts about the same service into a
+// correlated storm, so downstream services don't reason about every
+// individual AlertManager ping separately.
+package dedupe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// FP computes a stable fingerprint identifying "the same underlying
+// incident" across repeated notifications about it: where the alert came
+// from, what fired, and what it's about.
+func FP(source, alertName, service, namespace, severity string) string {
+	h := sha256.New()
+	for _, part := range []string{source, alertName, service, namespace, severity} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Entry is the cached record for a deduplicated alert fingerprint.
+type Entry struct {
+	EventID     string
+	RepeatCount int
+	FirstSeen   time.Time
+	LastSeen    time.Time
+}
+
+// Store is the pluggable backing store for deduplication state. The
+// in-memory MemoryStore is used by default; a Redis-backed Store can be
+// swapped in for multi-replica ingest deployments without changing the
+// ingest handler.
+type Store interface {
+	// Touch records a sighting of fingerprint at newEntry.LastSeen. If this
+	// is the first (or first non-expired) sighting, newEntry is stored
+	// as-is and hit is false. Otherwise the stored entry's RepeatCount is
+	// incremented and LastSeen is bumped, and the updated entry is returned
+	// with hit true.
+	Touch(fingerprint string, newEntry Entry) (entry Entry, hit bool)
+	// Close releases any resources held by the store.
+	Close() error
+}
@@ -0,0 +1,126 @@
+package dedupe
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// EvictFunc is invoked whenever a MemoryStore drops an entry, whether from
+// TTL expiry or the LRU size bound, so callers can track eviction metrics.
+type EvictFunc func(fingerprint string)
+
+type memoryEntry struct {
+	fingerprint string
+	entry       Entry
+	expiresAt   time.Time
+}
+
+// MemoryStore is a bounded, TTL-expiring in-memory Store. It evicts the
+// least-recently-used fingerprint once size is exceeded, and expires
+// entries older than ttl both lazily on access and via the periodic sweep
+// started by StartSweeper.
+type MemoryStore struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	onEvict EvictFunc
+
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// NewMemoryStore builds a MemoryStore bounded to size entries with the
+// given TTL. onEvict may be nil.
+func NewMemoryStore(size int, ttl time.Duration, onEvict EvictFunc) *MemoryStore {
+	return &MemoryStore{
+		size:    size,
+		ttl:     ttl,
+		onEvict: onEvict,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+// Touch implements Store.
+func (m *MemoryStore) Touch(fingerprint string, newEntry Entry) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.items[fingerprint]; ok {
+		me := el.Value.(*memoryEntry)
+		if newEntry.LastSeen.Before(me.expiresAt) {
+			me.entry.RepeatCount++
+			me.entry.LastSeen = newEntry.LastSeen
+			me.expiresAt = newEntry.LastSeen.Add(m.ttl)
+			m.ll.MoveToFront(el)
+			return me.entry, true
+		}
+		// Expired: treat this sighting as a fresh fingerprint.
+		m.removeElement(el)
+	}
+
+	m.insert(fingerprint, newEntry)
+	return newEntry, false
+}
+
+func (m *MemoryStore) insert(fingerprint string, entry Entry) {
+	el := m.ll.PushFront(&memoryEntry{
+		fingerprint: fingerprint,
+		entry:       entry,
+		expiresAt:   entry.LastSeen.Add(m.ttl),
+	})
+	m.items[fingerprint] = el
+
+	for m.size > 0 && m.ll.Len() > m.size {
+		if back := m.ll.Back(); back != nil {
+			m.removeElement(back)
+		}
+	}
+}
+
+func (m *MemoryStore) removeElement(el *list.Element) {
+	me := el.Value.(*memoryEntry)
+	m.ll.Remove(el)
+	delete(m.items, me.fingerprint)
+	if m.onEvict != nil {
+		m.onEvict(me.fingerprint)
+	}
+}
+
+// StartSweeper periodically evicts TTL-expired entries in the background.
+// It returns a stop function that halts the sweeper.
+func (m *MemoryStore) StartSweeper(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.evictExpired()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (m *MemoryStore) evictExpired() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for el := m.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if me := el.Value.(*memoryEntry); now.After(me.expiresAt) {
+			m.removeElement(el)
+		}
+		el = prev
+	}
+}
+
+// Close is a no-op for MemoryStore; it satisfies Store so callers can swap
+// in a Redis-backed implementation without changing call sites.
+func (m *MemoryStore) Close() error { return nil }
@@ -0,0 +1,144 @@
+// Package leaderelection wraps client-go's Lease-based leader election so a
+// service can run multiple replicas for availability while only one of them
+// actively does work that mutates cluster state.
+package leaderelection
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Elector reports this process's current leadership status.
+type Elector struct {
+	isLeader atomic.Bool
+}
+
+// IsLeader reports whether this process currently holds the lease. A nil
+// Elector (leader election not configured) always reports true, so callers
+// degrade gracefully to singleton behavior.
+func (e *Elector) IsLeader() bool {
+	if e == nil {
+		return true
+	}
+	return e.isLeader.Load()
+}
+
+// Config controls lease naming, identity, and timing.
+type Config struct {
+	Namespace     string
+	LeaseName     string
+	Identity      string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// ConfigFromEnv builds a Config from POD_NAMESPACE/LEADER_LEASE_NAME/
+// POD_NAME and LEADER_LEASE_DURATION_SECONDS / LEADER_RENEW_DEADLINE_SECONDS
+// / LEADER_RETRY_PERIOD_SECONDS, falling back to sane defaults so local dev
+// doesn't need to set anything.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Namespace:     os.Getenv("POD_NAMESPACE"),
+		LeaseName:     os.Getenv("LEADER_LEASE_NAME"),
+		Identity:      os.Getenv("POD_NAME"),
+		LeaseDuration: envSeconds("LEADER_LEASE_DURATION_SECONDS", 15*time.Second),
+		RenewDeadline: envSeconds("LEADER_RENEW_DEADLINE_SECONDS", 10*time.Second),
+		RetryPeriod:   envSeconds("LEADER_RETRY_PERIOD_SECONDS", 2*time.Second),
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+	if cfg.LeaseName == "" {
+		cfg.LeaseName = "incident-response-orchestrator-leader"
+	}
+	if cfg.Identity == "" {
+		if host, err := os.Hostname(); err == nil {
+			cfg.Identity = host
+		} else {
+			cfg.Identity = "unknown"
+		}
+	}
+	return cfg
+}
+
+func envSeconds(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs <= 0 {
+		return def
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// Run builds a Lease-backed elector in cfg.Namespace and starts the
+// election loop in the background; it returns immediately with an Elector
+// whose IsLeader() reflects live status. onStartedLeading/onStoppedLeading
+// are invoked on top of this package's own logging, so the caller can gate
+// or resume its work loop. After losing the lease the process keeps
+// campaigning for it; the election loop stops when ctx is cancelled.
+func Run(ctx context.Context, clientset kubernetes.Interface, cfg Config, onStartedLeading, onStoppedLeading func()) (*Elector, error) {
+	elector := &Elector{}
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		cfg.Namespace,
+		cfg.LeaseName,
+		clientset.CoreV1(),
+		clientset.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: cfg.Identity},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	leConfig := leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: cfg.LeaseDuration,
+		RenewDeadline: cfg.RenewDeadline,
+		RetryPeriod:   cfg.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				elector.isLeader.Store(true)
+				logger.Log.Info("Acquired leadership", zap.String("identity", cfg.Identity), zap.String("lease", cfg.LeaseName))
+				if onStartedLeading != nil {
+					onStartedLeading()
+				}
+			},
+			OnStoppedLeading: func() {
+				elector.isLeader.Store(false)
+				logger.Log.Info("Lost leadership", zap.String("identity", cfg.Identity), zap.String("lease", cfg.LeaseName))
+				if onStoppedLeading != nil {
+					onStoppedLeading()
+				}
+			},
+			OnNewLeader: func(identity string) {
+				if identity != cfg.Identity {
+					logger.Log.Info("Observed new leader", zap.String("leader", identity))
+				}
+			},
+		},
+	}
+
+	// RunOrDie returns once leadership is lost, so re-enter it until ctx is
+	// cancelled to keep this replica eligible to take over again.
+	go func() {
+		for ctx.Err() == nil {
+			leaderelection.RunOrDie(ctx, leConfig)
+		}
+	}()
+
+	return elector, nil
+}
@@ -54,6 +54,18 @@ var (
 		[]string{"decision"},
 	)
 
+	// PolicyRuleDecisions counts policy engine decisions segmented by the
+	// specific rule that decided the outcome (e.g. "forbidden_keyword",
+	// "rate_limit", "allow"), so operators can see which rule is firing
+	// instead of only the aggregate allow/deny/pending_approval split.
+	PolicyRuleDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "orchestrator_policy_rule_decisions_total",
+			Help: "Total number of policy decisions made, segmented by the rule that fired",
+		},
+		[]string{"rule", "decision"},
+	)
+
 	// KafkaMessagesPublished counts Kafka messages published
 	KafkaMessagesPublished = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -62,6 +74,76 @@ var (
 		},
 		[]string{"topic"},
 	)
+
+	// ApprovalsPending counts actions sent out for human approval
+	ApprovalsPending = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orchestrator_approvals_pending_total",
+			Help: "Total number of actions sent for human approval",
+		},
+	)
+
+	// ApprovalsApproved counts actions approved by a human
+	ApprovalsApproved = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orchestrator_approvals_approved_total",
+			Help: "Total number of actions approved by a human",
+		},
+	)
+
+	// ApprovalsDenied counts actions denied by a human
+	ApprovalsDenied = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orchestrator_approvals_denied_total",
+			Help: "Total number of actions denied by a human",
+		},
+	)
+
+	// Panics counts recovered goroutine panics, segmented by worker name
+	Panics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "orchestrator_panics_total",
+			Help: "Total number of goroutine panics recovered by pkg/runtime.HandleCrash",
+		},
+		[]string{"worker"},
+	)
+
+	// EnrichmentCacheLookups counts informer cache lookups made while
+	// enriching events, segmented by whether the service name resolved
+	EnrichmentCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "orchestrator_enrichment_cache_lookups_total",
+			Help: "Total number of router enrichment cache lookups",
+		},
+		[]string{"result"},
+	)
+
+	// DedupeHits counts alerts recognized as repeats of an in-flight
+	// fingerprint rather than a fresh event
+	DedupeHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orchestrator_dedupe_hits_total",
+			Help: "Total number of ingested alerts deduplicated against an existing fingerprint",
+		},
+	)
+
+	// CorrelationGroupsFormed counts alert storms correlated into a single
+	// events.correlated message
+	CorrelationGroupsFormed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orchestrator_correlation_groups_formed_total",
+			Help: "Total number of correlation groups formed from distinct alerts about the same service",
+		},
+	)
+
+	// DedupeCacheEvictions counts fingerprints dropped from the dedupe
+	// store, whether by TTL expiry or the LRU size bound
+	DedupeCacheEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "orchestrator_dedupe_cache_evictions_total",
+			Help: "Total number of fingerprints evicted from the dedupe store",
+		},
+	)
 )
 
 func init() {
@@ -71,7 +153,16 @@ func init() {
 		ProcessingDuration,
 		ActionsExecuted,
 		PolicyDecisions,
+		PolicyRuleDecisions,
 		KafkaMessagesPublished,
+		ApprovalsPending,
+		ApprovalsApproved,
+		ApprovalsDenied,
+		Panics,
+		EnrichmentCacheLookups,
+		DedupeHits,
+		CorrelationGroupsFormed,
+		DedupeCacheEvictions,
 	)
 }
 
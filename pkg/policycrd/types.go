@@ -0,0 +1,61 @@
+// Package policycrd contains the types and a hand-rolled clientset for the
+// RemediationPolicy CustomResourceDefinition that drives the policy engine.
+// In a generated-clientset setup these types and the Clientset in
+// clientset.go would come out of client-gen; they're written by hand here
+// so the CRD can be watched with the rest of the repo's client-go usage.
+package policycrd
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemediationPolicy mirrors the remediationpolicies.remediation.incidentresponseorchestrator.io
+// custom resource. A cluster operator edits one of these to change which
+// actions, namespaces, and rate limits the policy engine enforces without a
+// redeploy.
+type RemediationPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RemediationPolicySpec `json:"spec"`
+}
+
+// RemediationPolicySpec is the user-editable policy document.
+type RemediationPolicySpec struct {
+	// AllowedActions lists the action_type values the executor may run
+	// without requiring human approval.
+	AllowedActions []string `json:"allowedActions"`
+
+	// AllowedNamespaces lists namespaces actions may target without
+	// requiring human approval. kube-system is always denied regardless of
+	// this list.
+	AllowedNamespaces []string `json:"allowedNamespaces"`
+
+	// ForbiddenKeywords lists action_type substrings (case-insensitive) that
+	// are never permitted, e.g. "delete".
+	ForbiddenKeywords []string `json:"forbiddenKeywords"`
+
+	// RateLimits caps how many times an action may run against the same
+	// target within an hour before it is routed to approval. Only the first
+	// entry with a non-empty ActionType is applied, and its MaxPerHour
+	// becomes the limit for every action type; later entries are ignored.
+	RateLimits []RateLimitRule `json:"rateLimits,omitempty"`
+
+	// DryRun logs the decision the policy would have made without ever
+	// returning DecisionAllow, useful for rolling out a new policy safely.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// RateLimitRule bounds how often action_type may run against one target.
+type RateLimitRule struct {
+	ActionType string `json:"actionType"`
+	MaxPerHour int    `json:"maxPerHour"`
+}
+
+// RemediationPolicyList is the list type client-go needs for informers.
+type RemediationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RemediationPolicy `json:"items"`
+}
@@ -0,0 +1,33 @@
+// Package runtime holds small process-lifecycle helpers (panic recovery,
+// a context-aware HTTP server) shared by every service's main.go.
+package runtime
+
+import (
+	"runtime/debug"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+// HandleCrash launches fn in a goroutine guarded by panic recovery: if fn
+// panics, the stack trace is logged, orchestrator_panics_total is
+// incremented, and fn is re-launched so one bad message or one nil pointer
+// doesn't take the whole service down.
+func HandleCrash(worker string, fn func()) {
+	go runWithRecovery(worker, fn)
+}
+
+func runWithRecovery(worker string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.Panics.WithLabelValues(worker).Inc()
+			logger.Log.Error("Recovered from panic, restarting worker",
+				zap.String("worker", worker),
+				zap.Any("panic", r),
+				zap.String("stack", string(debug.Stack())))
+			go runWithRecovery(worker, fn)
+		}
+	}()
+	fn()
+}
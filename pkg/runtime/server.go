@@ -0,0 +1,40 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Server wraps http.Server so an HTTP endpoint (metrics, health, callbacks)
+// shuts down cleanly when the service's root context is cancelled instead
+// of being torn down mid-request by process exit.
+type Server struct {
+	httpServer *http.Server
+}
+
+// NewServer builds a Server listening on addr with the given handler.
+func NewServer(addr string, handler http.Handler) *Server {
+	return &Server{httpServer: &http.Server{Addr: addr, Handler: handler}}
+}
+
+// Run starts serving and blocks until ctx is cancelled, at which point it
+// gives in-flight requests up to 5s to finish before returning.
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return s.httpServer.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
@@ -0,0 +1,202 @@
+// Command approval-gateway consumes actions the policy engine sent out for
+// human approval, verifies the chat reply against an RBAC approver list, and
+// republishes the action as approved or rejected with an audit trail.
+package main
+
+import (
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/bot"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/metrics"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/services/approval-gateway/rbac"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	inputTopic    = "actions.pending_approval"
+	approvedTopic = "actions.approved"
+	rejectedTopic = "actions.rejected"
+	producer      *kafka.Producer
+	approvals     = bot.NewStore(15 * time.Minute)
+	approvers     *rbac.List
+)
+
+// buildNotifiers wires up the same chat adapters policy-engine posts
+// through, so this service can receive their inbound callbacks.
+func buildNotifiers() []bot.Bot {
+	var bots []bot.Bot
+	if url := os.Getenv("SLACK_WEBHOOK_URL"); url != "" {
+		bots = append(bots, bot.NewSlackBot(url))
+	}
+	if url := os.Getenv("DISCORD_WEBHOOK_URL"); url != "" {
+		bots = append(bots, bot.NewDiscordBot(url))
+	}
+	if url := os.Getenv("TEAMS_WEBHOOK_URL"); url != "" {
+		bots = append(bots, bot.NewTeamsBot(url))
+	}
+	return bots
+}
+
+func main() {
+	logger.InitLogger()
+	logger.Log.Info("Starting Approval Gateway Service...")
+
+	approvers = rbac.NewListFromEnv()
+	stopSweep := approvals.StartSweeper(time.Minute)
+	defer stopSweep()
+
+	notifiers := buildNotifiers()
+	responses := fanIn(notifiers)
+
+	mux := http.NewServeMux()
+	for _, n := range notifiers {
+		mux.HandleFunc("/callback/"+n.Name(), n.HandleCallback)
+	}
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		logger.Log.Info("Callback/metrics server listening on :9091")
+		http.ListenAndServe(":9091", mux)
+	}()
+
+	kafkaBroker := os.Getenv("KAFKA_BROKER")
+	if kafkaBroker == "" {
+		kafkaBroker = "localhost:9092"
+	}
+
+	var err error
+	producer, err = kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": kafkaBroker})
+	if err != nil {
+		logger.Log.Fatal("Failed to create producer", zap.Error(err))
+	}
+	defer producer.Close()
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": kafkaBroker,
+		"group.id":          "approval-gateway-group",
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		logger.Log.Fatal("Failed to create consumer", zap.Error(err))
+	}
+	defer consumer.Close()
+
+	consumer.SubscribeTopics([]string{inputTopic}, nil)
+
+	sigchan := make(chan os.Signal, 1)
+	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+
+	run := true
+	for run {
+		select {
+		case sig := <-sigchan:
+			logger.Log.Info("Caught signal, terminating", zap.String("signal", sig.String()))
+			run = false
+		case resp := <-responses:
+			handleResponse(resp)
+		default:
+			ev := consumer.Poll(100)
+			if ev == nil {
+				continue
+			}
+
+			switch e := ev.(type) {
+			case *kafka.Message:
+				stashPendingAction(e)
+			case kafka.Error:
+				logger.Log.Error("Kafka error", zap.Error(e))
+			}
+		}
+	}
+}
+
+// fanIn merges every bot's Responses channel into one so the main loop can
+// select on a single case alongside the Kafka poll.
+func fanIn(bots []bot.Bot) <-chan bot.ApprovalResponse {
+	out := make(chan bot.ApprovalResponse)
+	for _, b := range bots {
+		go func(b bot.Bot) {
+			for resp := range b.Responses() {
+				out <- resp
+			}
+		}(b)
+	}
+	return out
+}
+
+// stashPendingAction records an action.pending_approval message so it can be
+// reconstructed by ID once an approver replies in chat.
+func stashPendingAction(msg *kafka.Message) {
+	var action events.Action
+	if err := protojson.Unmarshal(msg.Value, &action); err != nil {
+		logger.Log.Error("Failed to unmarshal pending-approval action", zap.Error(err))
+		return
+	}
+
+	approvals.Put(bot.PendingApproval{
+		ActionID:   action.ActionId,
+		ActionType: action.ActionType,
+		Target:     action.Target,
+		CreatedAt:  time.Now(),
+		Payload:    append([]byte(nil), msg.Value...),
+	})
+	logger.Log.Info("Stashed action awaiting approval", zap.String("action_id", action.ActionId))
+}
+
+// handleResponse verifies the approver against the RBAC list and republishes
+// the action as approved or rejected with an audit trail.
+func handleResponse(resp bot.ApprovalResponse) {
+	if !approvers.IsApprover(resp.Approver) {
+		logger.Log.Warn("Approval reply from non-approver, ignoring",
+			zap.String("action_id", resp.ActionID), zap.String("approver", resp.Approver))
+		return
+	}
+
+	pending, ok := approvals.Take(resp.ActionID)
+	if !ok {
+		logger.Log.Warn("Approval reply for unknown or expired action",
+			zap.String("action_id", resp.ActionID), zap.String("approver", resp.Approver))
+		return
+	}
+
+	var action events.Action
+	if err := protojson.Unmarshal(pending.Payload, &action); err != nil {
+		logger.Log.Error("Failed to unmarshal stashed action", zap.Error(err))
+		return
+	}
+	action.Approver = resp.Approver
+
+	if resp.Approved {
+		publish(approvedTopic, &action)
+		metrics.ApprovalsApproved.Inc()
+		logger.Log.Info("Action approved", zap.String("action_id", action.ActionId), zap.String("approver", resp.Approver))
+	} else {
+		publish(rejectedTopic, &action)
+		metrics.ApprovalsDenied.Inc()
+		logger.Log.Info("Action denied", zap.String("action_id", action.ActionId), zap.String("approver", resp.Approver))
+	}
+}
+
+func publish(topic string, action *events.Action) {
+	val, err := protojson.Marshal(action)
+	if err != nil {
+		logger.Log.Error("Failed to marshal action", zap.Error(err))
+		return
+	}
+
+	err = producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Value:          val,
+	}, nil)
+	if err != nil {
+		logger.Log.Error("Failed to publish action", zap.String("topic", topic), zap.Error(err))
+	}
+}
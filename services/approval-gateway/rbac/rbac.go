@@ -0,0 +1,44 @@
+// Package rbac guards the approval-gateway against unauthorized approve/deny
+// replies by checking the responding chat user against a configured list of
+// approvers.
+package rbac
+
+import (
+	"os"
+	"strings"
+)
+
+// List is the set of usernames/handles allowed to approve or deny pending
+// actions. It is populated from the APPROVERS environment variable (a
+// comma-separated list) at startup.
+type List struct {
+	approvers map[string]bool
+}
+
+// NewListFromEnv builds a List from the comma-separated APPROVERS env var.
+func NewListFromEnv() *List {
+	var names []string
+	for _, name := range strings.Split(os.Getenv("APPROVERS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return NewList(names)
+}
+
+// NewList builds a List from an explicit set of approver names.
+func NewList(approvers []string) *List {
+	l := &List{approvers: make(map[string]bool, len(approvers))}
+	for _, a := range approvers {
+		if a != "" {
+			l.approvers[a] = true
+		}
+	}
+	return l
+}
+
+// IsApprover reports whether the given chat handle is allowed to approve or
+// deny actions.
+func (l *List) IsApprover(handle string) bool {
+	return l.approvers[handle]
+}
@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultMaxParallelism bounds ExecuteBulk's worker pool when
+// params["max_parallelism"] isn't set.
+const defaultMaxParallelism = 5
+
+// TargetResult is the per-target outcome of a bulk/fan-out action, letting
+// the caller summarize partial failures across a fleet instead of treating
+// every target as an independent action.
+type TargetResult struct {
+	Target             string
+	Started            time.Time
+	Finished           time.Time
+	Err                error
+	ObservedGeneration int64
+}
+
+// ExecuteBulk resolves targets — the explicit list if given, else a label
+// selector via params["selector"], else every Deployment in
+// params["namespace"] — and runs action against each one concurrently,
+// bounded by params["max_parallelism"] and ctx's deadline.
+func ExecuteBulk(ctx context.Context, action string, targets []string, params map[string]string) []TargetResult {
+	resolved, err := resolveTargets(ctx, targets, params)
+	if err != nil {
+		return []TargetResult{{Started: time.Now(), Finished: time.Now(), Err: err}}
+	}
+
+	parallelism := defaultMaxParallelism
+	if v, ok := params["max_parallelism"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			parallelism = n
+		}
+	}
+
+	results := make([]TargetResult, len(resolved))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, target := range resolved {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = TargetResult{Target: target, Started: time.Now(), Finished: time.Now(), Err: ctx.Err()}
+				return
+			}
+
+			results[i] = runOne(ctx, action, target, params)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// resolveTargets turns the bulk request's target spec into a concrete list
+// of "kind/name" strings.
+func resolveTargets(ctx context.Context, targets []string, params map[string]string) ([]string, error) {
+	if len(targets) > 0 {
+		return targets, nil
+	}
+
+	if Clientset == nil {
+		return nil, fmt.Errorf("k8s client not initialized, cannot resolve bulk targets")
+	}
+
+	namespace := params["namespace"]
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	listOpts := v1.ListOptions{}
+	if sel := params["selector"]; sel != "" {
+		listOpts.LabelSelector = sel
+	}
+
+	deployments, err := Clientset.AppsV1().Deployments(namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]string, 0, len(deployments.Items))
+	for _, d := range deployments.Items {
+		resolved = append(resolved, "deployment/"+d.Name)
+	}
+	return resolved, nil
+}
+
+// runOne executes action against a single target and records its outcome,
+// including the deployment's ObservedGeneration afterward so callers can
+// tell a no-op from a real rollout.
+func runOne(ctx context.Context, action, target string, params map[string]string) TargetResult {
+	result := TargetResult{Target: target, Started: time.Now()}
+
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		result.Finished = time.Now()
+		return result
+	}
+
+	switch action {
+	case "restart_pod":
+		result.Err = RestartPodCtx(ctx, target, params)
+	case "scale_deployment":
+		result.Err = ScaleDeploymentCtx(ctx, target, params)
+	case "rolling_restart_deployment":
+		result.Err = RollingRestartDeploymentCtx(ctx, target, params)
+	case "rollback_deployment":
+		result.Err = RollbackDeploymentCtx(ctx, target, params)
+	default:
+		result.Err = fmt.Errorf("unknown bulk action type: %s", action)
+	}
+
+	result.Finished = time.Now()
+	result.ObservedGeneration = observedGeneration(target, params)
+	return result
+}
+
+func observedGeneration(target string, params map[string]string) int64 {
+	if Clientset == nil || !strings.HasPrefix(target, "deployment/") {
+		return 0
+	}
+	namespace := params["namespace"]
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	deploy, err := Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), strings.TrimPrefix(target, "deployment/"), v1.GetOptions{})
+	if err != nil {
+		return 0
+	}
+	return deploy.Status.ObservedGeneration
+}
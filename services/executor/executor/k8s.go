@@ -2,15 +2,19 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -18,6 +22,18 @@ import (
 	"k8s.io/client-go/util/homedir"
 )
 
+// revisionAnnotation is the annotation the Deployment controller stamps
+// onto both a Deployment and its ReplicaSets to track rollout history.
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// ErrNoPriorRevision means the deployment has no earlier ReplicaSet to roll
+// back to.
+var ErrNoPriorRevision = errors.New("no prior revision to roll back to")
+
+// ErrRevisionNotFound means params["to_revision"] didn't match any
+// ReplicaSet's revision annotation, or the deployment itself doesn't exist.
+var ErrRevisionNotFound = errors.New("revision not found")
+
 var Clientset *kubernetes.Clientset
 
 func InitK8sClient() {
@@ -44,9 +60,21 @@ func InitK8sClient() {
 		logger.Log.Fatal("Failed to create k8s client", zap.Error(err))
 	}
 	logger.Log.Info("K8s client initialized successfully")
+
+	startInformers(Clientset)
 }
 
+// RestartPod restarts target against the background context. It exists
+// alongside RestartPodCtx for callers (single-target actions from main.go)
+// that have no caller-supplied deadline to thread through.
 func RestartPod(target string, params map[string]string) error {
+	return RestartPodCtx(context.Background(), target, params)
+}
+
+// RestartPodCtx is RestartPod with an explicit parent context, so a bulk
+// action's shared deadline (see ExecuteBulk) bounds this target too instead
+// of each target getting its own fresh per-action timeout.
+func RestartPodCtx(ctx context.Context, target string, params map[string]string) error {
 	// If target lacks "pod/" prefix and looks like a service/deployment name, prefer Rolling Restart
 	if !strings.HasPrefix(target, "pod/") && !strings.Contains(target, "-") {
 		// Heuristic: If it's just "kafka-ingest", it's likely a deployment.
@@ -67,24 +95,41 @@ func RestartPod(target string, params map[string]string) error {
 
 	// If explicit "deployment/" prefix or simple name that might be a deployment
 	if strings.HasPrefix(target, "deployment/") {
-		return RollingRestartDeployment(target, params)
+		return RollingRestartDeploymentCtx(ctx, target, params)
 	}
 
 	podName := strings.TrimPrefix(target, "pod/")
 
-	err := Clientset.CoreV1().Pods(namespace).Delete(context.TODO(), podName, v1.DeleteOptions{})
+	existing, err := Clientset.CoreV1().Pods(namespace).Get(ctx, podName, v1.GetOptions{})
 	if err != nil {
-		// If failed to delete pod (e.g. not found), and it looks like a deployment name, try rolling restart
+		// If the pod doesn't exist, and it looks like a deployment name, try rolling restart
 		if strings.Contains(err.Error(), "not found") {
 			logger.Log.Info("Pod not found, attempting Rolling Restart of deployment", zap.String("target", target))
-			return RollingRestartDeployment(target, params)
+			return RollingRestartDeploymentCtx(ctx, target, params)
 		}
 		return err
 	}
-	return nil
+
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout(params))
+	defer cancel()
+
+	if err := retryOnConflict(ctx, params, func() error {
+		return Clientset.CoreV1().Pods(namespace).Delete(ctx, podName, v1.DeleteOptions{})
+	}); err != nil {
+		return err
+	}
+
+	return WaitForPodRestart(ctx, namespace, podName, existing)
 }
 
+// ScaleDeployment scales target against the background context; see
+// ScaleDeploymentCtx for the bulk-action path.
 func ScaleDeployment(target string, params map[string]string) error {
+	return ScaleDeploymentCtx(context.Background(), target, params)
+}
+
+// ScaleDeploymentCtx is ScaleDeployment with an explicit parent context.
+func ScaleDeploymentCtx(ctx context.Context, target string, params map[string]string) error {
 	if Clientset == nil {
 		logger.Log.Warn("SIMULATION MODE: K8s client not available. Pretending to scale.",
 			zap.String("target", target),
@@ -99,44 +144,67 @@ func ScaleDeployment(target string, params map[string]string) error {
 	}
 	deploymentName := strings.TrimPrefix(target, "deployment/")
 
-	// 1. Get current deployment
-	deploy, err := Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, v1.GetOptions{})
-	if err != nil {
-		return err
-	}
-
-	// 2. Calculate new replicas
-	currentReplicas := *deploy.Spec.Replicas
-	var newReplicas int32
-
-	if val, ok := params["replicas"]; ok {
-		// Absolute value
-		fmt.Sscanf(val, "%d", &newReplicas)
-	} else if val, ok := params["replicas_increment"]; ok {
-		var inc int32
-		fmt.Sscanf(val, "%d", &inc)
-		newReplicas = currentReplicas + inc
-	} else if val, ok := params["replicas_increase"]; ok {
-		var inc int32
-		fmt.Sscanf(val, "%d", &inc)
-		newReplicas = currentReplicas + inc
-	} else {
+	_, hasAbs := params["replicas"]
+	_, hasIncr := params["replicas_increment"]
+	_, hasIncr2 := params["replicas_increase"]
+	if !hasAbs && !hasIncr && !hasIncr2 {
 		return fmt.Errorf("missing replicas, replicas_increment, or replicas_increase param")
 	}
 
-	logger.Log.Info("Scaling deployment",
-		zap.String("deployment", deploymentName),
-		zap.Int32("current", currentReplicas),
-		zap.Int32("new", newReplicas))
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout(params))
+	defer cancel()
+
+	var targetGeneration int64
+	if err := retryOnConflict(ctx, params, func() error {
+		// Re-Get on every attempt so the resourceVersion we Update is
+		// always current, not just on the first try.
+		deploy, err := Clientset.AppsV1().Deployments(namespace).Get(ctx, deploymentName, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		currentReplicas := *deploy.Spec.Replicas
+		newReplicas := currentReplicas
+
+		if val, ok := params["replicas"]; ok {
+			fmt.Sscanf(val, "%d", &newReplicas)
+		} else if val, ok := params["replicas_increment"]; ok {
+			var inc int32
+			fmt.Sscanf(val, "%d", &inc)
+			newReplicas = currentReplicas + inc
+		} else if val, ok := params["replicas_increase"]; ok {
+			var inc int32
+			fmt.Sscanf(val, "%d", &inc)
+			newReplicas = currentReplicas + inc
+		}
+
+		logger.Log.Info("Scaling deployment",
+			zap.String("deployment", deploymentName),
+			zap.Int32("current", currentReplicas),
+			zap.Int32("new", newReplicas))
 
-	deploy.Spec.Replicas = &newReplicas
+		deploy.Spec.Replicas = &newReplicas
+		updated, err := Clientset.AppsV1().Deployments(namespace).Update(ctx, deploy, v1.UpdateOptions{})
+		if err == nil {
+			targetGeneration = updated.Generation
+		}
+		return err
+	}); err != nil {
+		return err
+	}
 
-	// 3. Update
-	_, err = Clientset.AppsV1().Deployments(namespace).Update(context.TODO(), deploy, v1.UpdateOptions{})
-	return err
+	return WaitForDeploymentRollout(ctx, namespace, deploymentName, targetGeneration)
 }
 
+// RollingRestartDeployment rolling-restarts target against the background
+// context; see RollingRestartDeploymentCtx for the bulk-action path.
 func RollingRestartDeployment(target string, params map[string]string) error {
+	return RollingRestartDeploymentCtx(context.Background(), target, params)
+}
+
+// RollingRestartDeploymentCtx is RollingRestartDeployment with an explicit
+// parent context.
+func RollingRestartDeploymentCtx(ctx context.Context, target string, params map[string]string) error {
 	if Clientset == nil {
 		logger.Log.Warn("SIMULATION MODE: K8s client not available. Pretending to rollout restart.",
 			zap.String("target", target))
@@ -152,42 +220,158 @@ func RollingRestartDeployment(target string, params map[string]string) error {
 
 	logger.Log.Info("Triggering rolling restart", zap.String("deployment", deploymentName))
 
-	// Get deployment
-	deploy, err := Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, v1.GetOptions{})
-	if err != nil {
-		return err
-	}
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout(params))
+	defer cancel()
 
-	// Update annotation to trigger rollout
-	if deploy.Spec.Template.Annotations == nil {
-		deploy.Spec.Template.Annotations = make(map[string]string)
+	var targetGeneration int64
+	if err := retryOnConflict(ctx, params, func() error {
+		// Re-Get on every attempt so the resourceVersion we Update is
+		// always current, not just on the first try.
+		deploy, err := Clientset.AppsV1().Deployments(namespace).Get(ctx, deploymentName, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if deploy.Spec.Template.Annotations == nil {
+			deploy.Spec.Template.Annotations = make(map[string]string)
+		}
+		deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
+
+		updated, err := Clientset.AppsV1().Deployments(namespace).Update(ctx, deploy, v1.UpdateOptions{})
+		if err == nil {
+			targetGeneration = updated.Generation
+		}
+		return err
+	}); err != nil {
+		return err
 	}
-	deploy.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
 
-	_, err = Clientset.AppsV1().Deployments(namespace).Update(context.TODO(), deploy, v1.UpdateOptions{})
-	return err
+	return WaitForDeploymentRollout(ctx, namespace, deploymentName, targetGeneration)
 }
 
-// RollbackDeployment performs a rollback using kubectl rollout undo
+// RollbackDeployment rolls target back against the background context; see
+// RollbackDeploymentCtx for the bulk-action path.
 func RollbackDeployment(target string, params map[string]string) error {
+	return RollbackDeploymentCtx(context.Background(), target, params)
+}
+
+// RollbackDeploymentCtx rolls a deployment back to an earlier ReplicaSet's
+// pod template using only client-go: no kubectl binary required, and it can
+// target a specific revision via params["to_revision"].
+func RollbackDeploymentCtx(ctx context.Context, target string, params map[string]string) error {
+	if Clientset == nil {
+		logger.Log.Warn("SIMULATION MODE: K8s client not available. Pretending to rollback.",
+			zap.String("target", target))
+		time.Sleep(2 * time.Second)
+		return nil
+	}
+
 	deploymentName := strings.TrimPrefix(target, "deployment/")
 	namespace := params["namespace"]
 	if namespace == "" {
 		namespace = "default"
 	}
 
-	logger.Log.Info("Attempting Rollback (kubectl rollout undo)",
-		zap.String("deployment", deploymentName),
-		zap.String("namespace", namespace))
+	logger.Log.Info("Attempting Rollback", zap.String("deployment", deploymentName), zap.String("namespace", namespace))
+
+	deploy, err := Clientset.AppsV1().Deployments(namespace).Get(ctx, deploymentName, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("deployment %s/%s: %w", namespace, deploymentName, ErrRevisionNotFound)
+		}
+		return err
+	}
+
+	selector, err := v1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector on deployment %s/%s: %w", namespace, deploymentName, err)
+	}
 
-	// Execute kubectl command
-	cmd := exec.Command("kubectl", "rollout", "undo", "deployment/"+deploymentName, "-n", namespace)
-	output, err := cmd.CombinedOutput()
+	rsList, err := Clientset.AppsV1().ReplicaSets(namespace).List(ctx, v1.ListOptions{LabelSelector: selector.String()})
 	if err != nil {
-		logger.Log.Error("Rollback failed", zap.String("output", string(output)), zap.Error(err))
-		return fmt.Errorf("kubectl rollback failed: %s (%v)", string(output), err)
+		return err
+	}
+
+	targetRS, err := selectRevision(rsList.Items, deploy, params["to_revision"])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout(params))
+	defer cancel()
+
+	fromRevision := deploy.Annotations[revisionAnnotation]
+	var targetGeneration int64
+	if err := retryOnConflict(ctx, params, func() error {
+		// Re-Get on every attempt so the resourceVersion we Update is
+		// always current, not just on the first try.
+		current, err := Clientset.AppsV1().Deployments(namespace).Get(ctx, deploymentName, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		current.Spec.Template = targetRS.Spec.Template
+		if current.Spec.Template.Annotations == nil {
+			current.Spec.Template.Annotations = make(map[string]string)
+		}
+		current.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
+
+		updated, err := Clientset.AppsV1().Deployments(namespace).Update(ctx, current, v1.UpdateOptions{})
+		if err == nil {
+			targetGeneration = updated.Generation
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+
+	logger.Log.Info("Rollback applied", zap.String("deployment", deploymentName), zap.String("from_revision", fromRevision))
+
+	return WaitForDeploymentRollout(ctx, namespace, deploymentName, targetGeneration)
+}
+
+// selectRevision picks the ReplicaSet to roll back to: the one named by
+// toRevision if set, otherwise the most recent ReplicaSet whose revision
+// isn't the deployment's current one.
+func selectRevision(rsItems []appsv1.ReplicaSet, deploy *appsv1.Deployment, toRevision string) (*appsv1.ReplicaSet, error) {
+	type candidate struct {
+		rs       appsv1.ReplicaSet
+		revision int
 	}
 
-	logger.Log.Info("Rollback successful", zap.String("output", string(output)))
-	return nil
+	var candidates []candidate
+	for _, rs := range rsItems {
+		revStr, ok := rs.Annotations[revisionAnnotation]
+		if !ok {
+			continue
+		}
+		rev, err := strconv.Atoi(revStr)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{rs: rs, revision: rev})
+	}
+
+	if toRevision != "" {
+		want, err := strconv.Atoi(toRevision)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to_revision %q: %w", toRevision, ErrRevisionNotFound)
+		}
+		for _, c := range candidates {
+			if c.revision == want {
+				return &c.rs, nil
+			}
+		}
+		return nil, fmt.Errorf("revision %d: %w", want, ErrRevisionNotFound)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].revision > candidates[j].revision })
+
+	currentRevision, _ := strconv.Atoi(deploy.Annotations[revisionAnnotation])
+	for _, c := range candidates {
+		if c.revision != currentRevision {
+			return &c.rs, nil
+		}
+	}
+	return nil, ErrNoPriorRevision
 }
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+)
+
+// defaultMaxRetries bounds the exponential backoff loop when
+// params["max_retries"] isn't set.
+const defaultMaxRetries = 5
+
+const (
+	backoffBase = 100 * time.Millisecond
+	backoffCap  = 2 * time.Second
+)
+
+// Classified errors a caller (main.go's publishBulkCompletion, via
+// ClassificationLabel) can Is()-check to decide whether an action is worth auto-retrying upstream
+// or should go straight to escalation.
+var (
+	ErrNotFound  = errors.New("not_found")
+	ErrConflict  = errors.New("conflict")
+	ErrForbidden = errors.New("forbidden")
+	ErrTimeout   = errors.New("timeout")
+	ErrUnknown   = errors.New("unknown")
+)
+
+// ClassificationLabel returns the short string form of Classify(err)'s
+// sentinel ("not_found", "conflict", ...), or "" for a nil error, for
+// callers (e.g. the events.action_completed payload) that want a label
+// rather than an error value.
+func ClassificationLabel(err error) string {
+	switch classified := Classify(err); {
+	case classified == nil:
+		return ""
+	case errors.Is(classified, ErrNotFound):
+		return "not_found"
+	case errors.Is(classified, ErrConflict):
+		return "conflict"
+	case errors.Is(classified, ErrForbidden):
+		return "forbidden"
+	case errors.Is(classified, ErrTimeout):
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// Classify maps a k8s API error to one of this package's sentinel errors,
+// wrapping the original so callers can still inspect it via errors.Is/errors.As.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case k8serrors.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case k8serrors.IsConflict(err):
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	case k8serrors.IsForbidden(err):
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	case k8serrors.IsServerTimeout(err), k8serrors.IsTooManyRequests(err), k8serrors.IsInternalError(err), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrUnknown, err)
+	}
+}
+
+// retryOnConflict wraps fn with client-go's conflict retry for IsConflict
+// errors, and its own exponential backoff (100ms->2s, capped by
+// params["max_retries"] or defaultMaxRetries) for IsServerTimeout/
+// IsTooManyRequests/IsInternalError. Any other error is returned immediately,
+// classified via Classify.
+func retryOnConflict(ctx context.Context, params map[string]string, fn func() error) error {
+	maxRetries := defaultMaxRetries
+	if v, ok := params["max_retries"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			maxRetries = n
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return Classify(err)
+		}
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, fn)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if k8serrors.IsConflict(err) {
+			// RetryOnConflict already exhausted its own attempts; fall
+			// through to classification rather than looping again.
+			return Classify(err)
+		}
+		if !(k8serrors.IsServerTimeout(err) || k8serrors.IsTooManyRequests(err) || k8serrors.IsInternalError(err)) {
+			return Classify(err)
+		}
+		if attempt == maxRetries {
+			break
+		}
+
+		wait := backoff(attempt)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return Classify(ctx.Err())
+		}
+	}
+
+	return Classify(lastErr)
+}
+
+// backoff returns an exponentially increasing, jittered delay for the given
+// zero-based attempt number, capped at backoffCap.
+func backoff(attempt int) time.Duration {
+	d := backoffBase << attempt
+	if d > backoffCap || d <= 0 {
+		d = backoffCap
+	}
+	jitter := time.Duration(rand.Int63n(int64(d) / 2))
+	return d/2 + jitter
+}
@@ -0,0 +1,302 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// defaultWaitTimeout bounds how long a WaitFor... call blocks when the
+// action's params don't set "timeout".
+const defaultWaitTimeout = 2 * time.Minute
+
+// ActionEvent reports intermediate progress of a long-running action (a pod
+// restart or a deployment rollout) as the informer cache observes it, so
+// main can publish it back onto Kafka as events.action_progress instead of
+// the caller only ever seeing the final result.
+type ActionEvent struct {
+	Target    string
+	Phase     string
+	Message   string
+	Timestamp time.Time
+}
+
+// Events carries intermediate progress reported by the WaitFor... helpers.
+// It's buffered so a slow consumer can't block an in-flight action; main
+// should drain it in a dedicated goroutine.
+var Events = make(chan ActionEvent, 100)
+
+func reportProgress(target, phase, message string) {
+	select {
+	case Events <- ActionEvent{Target: target, Phase: phase, Message: message, Timestamp: time.Now()}:
+	default:
+		logger.Log.Warn("Dropped action progress event, Events channel full", zap.String("target", target))
+	}
+}
+
+var (
+	informerFactory informers.SharedInformerFactory
+	podInformer     cache.SharedIndexInformer
+	deployInformer  cache.SharedIndexInformer
+
+	waitersMu     sync.Mutex
+	podWaiters    = make(map[string]chan *corev1.Pod)
+	deployWaiters = make(map[string]chan *appsv1.Deployment)
+)
+
+// startInformers builds and starts the singleton Pod/Deployment informer
+// factory backing the WaitFor... helpers below, so executor actions don't
+// re-list the API server on every call. Called once from InitK8sClient.
+func startInformers(clientset kubernetes.Interface) {
+	informerFactory = informers.NewSharedInformerFactory(clientset, 10*time.Minute)
+	podInformer = informerFactory.Core().V1().Pods().Informer()
+	deployInformer = informerFactory.Apps().V1().Deployments().Informer()
+
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    notifyPod,
+		UpdateFunc: func(_, obj interface{}) { notifyPod(obj) },
+	})
+	deployInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    notifyDeployment,
+		UpdateFunc: func(_, obj interface{}) { notifyDeployment(obj) },
+	})
+
+	stopCh := make(chan struct{})
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+	logger.Log.Info("Executor informer cache synced")
+}
+
+func notifyPod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	waitersMu.Lock()
+	defer waitersMu.Unlock()
+	for _, key := range podWaitKeys(pod) {
+		if ch, exists := podWaiters[key]; exists {
+			sendLatestPod(ch, pod)
+		}
+	}
+}
+
+// podWaitKeys returns every key a WaitForPodRestart call might be
+// registered under for pod: its namespace/name, and, if it's
+// controller-owned (ReplicaSet, StatefulSet, ...), its owning controller's
+// UID too, so a waiter keyed on either still sees this pod's events.
+func podWaitKeys(pod *corev1.Pod) []string {
+	keys := []string{pod.Namespace + "/" + pod.Name}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		keys = append(keys, pod.Namespace+"/owner/"+string(owner.UID))
+	}
+	return keys
+}
+
+func notifyDeployment(obj interface{}) {
+	deploy, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	waitersMu.Lock()
+	ch, exists := deployWaiters[deploy.Namespace+"/"+deploy.Name]
+	waitersMu.Unlock()
+	if exists {
+		sendLatestDeployment(ch, deploy)
+	}
+}
+
+// sendLatestPod keeps only the newest observation in ch, so a waiter that's
+// busy evaluating one update never blocks the informer's event loop.
+func sendLatestPod(ch chan *corev1.Pod, pod *corev1.Pod) {
+	select {
+	case ch <- pod:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- pod:
+	default:
+	}
+}
+
+func sendLatestDeployment(ch chan *appsv1.Deployment, deploy *appsv1.Deployment) {
+	select {
+	case ch <- deploy:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- deploy:
+	default:
+	}
+}
+
+// waitTimeout reads params["timeout"] as a count of seconds, falling back
+// to defaultWaitTimeout if it's unset or invalid.
+func waitTimeout(params map[string]string) time.Duration {
+	if v, ok := params["timeout"]; ok {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return defaultWaitTimeout
+}
+
+// WaitForPodRestart blocks until oldPod's replacement is observed Running.
+// A controller-owned pod (ReplicaSet, StatefulSet, ...) is almost always
+// recreated under a brand new name, so "the replacement" is resolved by
+// owner UID rather than by name wherever oldPod has one; only a standalone
+// pod falls back to matching on namespace/name with a new UID.
+func WaitForPodRestart(ctx context.Context, namespace, podName string, oldPod *corev1.Pod) error {
+	if Clientset == nil || podInformer == nil {
+		return nil
+	}
+
+	owner := metav1.GetControllerOf(oldPod)
+	key := namespace + "/" + podName
+	if owner != nil {
+		key = namespace + "/owner/" + string(owner.UID)
+	}
+
+	ch := make(chan *corev1.Pod, 1)
+
+	waitersMu.Lock()
+	podWaiters[key] = ch
+	waitersMu.Unlock()
+	defer func() {
+		waitersMu.Lock()
+		delete(podWaiters, key)
+		waitersMu.Unlock()
+	}()
+
+	// The informer may already have the replacement cached by the time we
+	// register, so check current state before waiting on an update.
+	if pod := findRestartedPod(namespace, podName, owner, oldPod.UID); pod != nil {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pod %s to restart: %w", key, ctx.Err())
+		case pod := <-ch:
+			if podIsReplacement(pod, podName, owner, oldPod.UID) {
+				reportProgress("pod/"+podName, "restarted", "replacement pod is Running")
+				return nil
+			}
+		}
+	}
+}
+
+// findRestartedPod scans the informer's current cache for a replacement,
+// for the case where it landed before the caller finished registering its
+// waiter channel.
+func findRestartedPod(namespace, podName string, owner *metav1.OwnerReference, oldUID types.UID) *corev1.Pod {
+	for _, obj := range podInformer.GetStore().List() {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Namespace != namespace {
+			continue
+		}
+		if podIsReplacement(pod, podName, owner, oldUID) {
+			return pod
+		}
+	}
+	return nil
+}
+
+// podIsReplacement reports whether pod is a Running replacement for the pod
+// that was deleted: the same controller's new pod if owner is non-nil
+// (matched by owner UID, since the replacement is usually a new name),
+// otherwise the same name reappearing with a new UID.
+func podIsReplacement(pod *corev1.Pod, podName string, owner *metav1.OwnerReference, oldUID types.UID) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.UID == oldUID {
+		return false
+	}
+	if owner != nil {
+		podOwner := metav1.GetControllerOf(pod)
+		return podOwner != nil && podOwner.UID == owner.UID
+	}
+	return pod.Name == podName
+}
+
+// WaitForDeploymentRollout blocks until namespace/name's rollout has fully
+// landed: the API server has accepted a spec generation at least as new as
+// targetGeneration (the generation returned by the mutating Update call),
+// the controller has observed it, and every desired replica is both updated
+// and available. Requiring targetGeneration (rather than just comparing a
+// cached object's ObservedGeneration to its own Generation) is what keeps
+// this from reporting success against a still-stale informer cache entry
+// from before the mutation landed.
+func WaitForDeploymentRollout(ctx context.Context, namespace, name string, targetGeneration int64) error {
+	if Clientset == nil || deployInformer == nil {
+		return nil
+	}
+
+	key := namespace + "/" + name
+	ch := make(chan *appsv1.Deployment, 1)
+
+	waitersMu.Lock()
+	deployWaiters[key] = ch
+	waitersMu.Unlock()
+	defer func() {
+		waitersMu.Lock()
+		delete(deployWaiters, key)
+		waitersMu.Unlock()
+	}()
+
+	if obj, exists, _ := deployInformer.GetStore().GetByKey(key); exists {
+		if deploy, ok := obj.(*appsv1.Deployment); ok && rolloutComplete(deploy, targetGeneration) {
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for deployment %s rollout: %w", key, ctx.Err())
+		case deploy := <-ch:
+			reportProgress("deployment/"+name, "rolling-out", fmt.Sprintf(
+				"updated=%d available=%d desired=%d", deploy.Status.UpdatedReplicas, deploy.Status.AvailableReplicas, desiredReplicas(deploy)))
+			if rolloutComplete(deploy, targetGeneration) {
+				reportProgress("deployment/"+name, "rolled-out", "rollout complete")
+				return nil
+			}
+		}
+	}
+}
+
+func desiredReplicas(deploy *appsv1.Deployment) int32 {
+	if deploy.Spec.Replicas == nil {
+		return 1
+	}
+	return *deploy.Spec.Replicas
+}
+
+func rolloutComplete(deploy *appsv1.Deployment, targetGeneration int64) bool {
+	desired := desiredReplicas(deploy)
+	return deploy.Generation >= targetGeneration &&
+		deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == desired &&
+		deploy.Status.AvailableReplicas == desired
+}
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/runtime"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/services/executor/executor"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -19,21 +23,34 @@ import (
 )
 
 var (
-	inputTopic  = "actions.approved"
-	outputTopic = "actions.status"
-	producer    *kafka.Producer
+	inputTopic           = "actions.approved"
+	outputTopic          = "actions.status"
+	actionProgressTopic  = "events.action_progress"
+	actionCompletedTopic = "events.action_completed"
 )
 
+// ExecutorService owns the Kafka clients for the Remediation Executor. It
+// replaces the old package-level producer/consumer globals so shutdown can
+// drain and flush a specific instance instead of reaching for ambient state.
+type ExecutorService struct {
+	producer *kafka.Producer
+	consumer *kafka.Consumer
+}
+
 func main() {
 	logger.InitLogger()
 	logger.Log.Info("Starting Remediation Executor Service...")
 
-	// Start metrics server on port 9090
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	metricsServer := runtime.NewServer(":9090", promhttp.Handler())
+	runtime.HandleCrash("executor-metrics-server", func() {
 		logger.Log.Info("Metrics server listening on :9090")
-		http.ListenAndServe(":9090", nil)
-	}()
+		if err := metricsServer.Run(ctx); err != nil {
+			logger.Log.Error("Metrics server failed", zap.Error(err))
+		}
+	})
 
 	executor.InitK8sClient()
 
@@ -42,12 +59,10 @@ func main() {
 		kafkaBroker = "localhost:9092"
 	}
 
-	var err error
-	producer, err = kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": kafkaBroker})
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": kafkaBroker})
 	if err != nil {
 		logger.Log.Fatal("Failed to create producer", zap.Error(err))
 	}
-	defer producer.Close()
 
 	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers": kafkaBroker,
@@ -57,28 +72,61 @@ func main() {
 	if err != nil {
 		logger.Log.Fatal("Failed to create consumer", zap.Error(err))
 	}
-	defer consumer.Close()
 
-	consumer.SubscribeTopics([]string{inputTopic}, nil)
+	svc := &ExecutorService{producer: producer, consumer: consumer}
+	if err := consumer.SubscribeTopics([]string{inputTopic}, nil); err != nil {
+		logger.Log.Fatal("Failed to subscribe to input topic", zap.Error(err))
+	}
+
+	runtime.HandleCrash("executor-progress-publisher", func() { svc.publishProgress(ctx) })
+
+	svc.run(ctx)
+	svc.shutdown()
+}
+
+// publishProgress drains executor.Events, the WaitFor... helpers' channel
+// of intermediate rollout/restart observations, and republishes each one
+// onto Kafka as events.action_progress so the rest of the system can watch
+// an action land instead of only seeing its terminal status.
+func (s *ExecutorService) publishProgress(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-executor.Events:
+			val, err := json.Marshal(ev)
+			if err != nil {
+				logger.Log.Error("Failed to marshal action progress event", zap.Error(err))
+				continue
+			}
 
-	sigchan := make(chan os.Signal, 1)
-	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+			if err := s.producer.Produce(&kafka.Message{
+				TopicPartition: kafka.TopicPartition{Topic: &actionProgressTopic, Partition: kafka.PartitionAny},
+				Value:          val,
+			}, nil); err != nil {
+				logger.Log.Error("Failed to publish action progress event", zap.Error(err))
+			}
+		}
+	}
+}
 
-	run := true
-	for run {
+// run polls for approved actions until ctx is cancelled, at which point it
+// returns so main can drain and flush.
+func (s *ExecutorService) run(ctx context.Context) {
+	for {
 		select {
-		case sig := <-sigchan:
-			logger.Log.Info("Caught signal, terminating", zap.String("signal", sig.String()))
-			run = false
+		case <-ctx.Done():
+			logger.Log.Info("Context cancelled, stopping poll loop")
+			return
 		default:
-			ev := consumer.Poll(100)
+			ev := s.consumer.Poll(100)
 			if ev == nil {
 				continue
 			}
 
 			switch e := ev.(type) {
 			case *kafka.Message:
-				processAction(e)
+				s.processAction(e)
 			case kafka.Error:
 				logger.Log.Error("Kafka error", zap.Error(e))
 			}
@@ -86,14 +134,31 @@ func main() {
 	}
 }
 
-func processAction(msg *kafka.Message) {
+// shutdown flushes any in-flight status reports before closing the Kafka
+// clients, so a SIGTERM during an action doesn't lose its outcome.
+func (s *ExecutorService) shutdown() {
+	logger.Log.Info("Shutting down, flushing producer...")
+	if unflushed := s.producer.Flush(10000); unflushed > 0 {
+		logger.Log.Warn("Producer flush timed out with messages still queued", zap.Int("unflushed", unflushed))
+	}
+	s.consumer.Close()
+	s.producer.Close()
+}
+
+func (s *ExecutorService) processAction(msg *kafka.Message) {
 	var action events.Action
 	if err := protojson.Unmarshal(msg.Value, &action); err != nil {
 		logger.Log.Error("Failed to unmarshal action", zap.Error(err))
 		return
 	}
 
-	logger.Log.Info("Executing action", zap.String("action_id", action.ActionId), zap.String("type", action.ActionType))
+	logger.Log.Info("Executing action",
+		zap.String("action_id", action.ActionId), zap.String("event_id", action.EventId), zap.String("type", action.ActionType))
+
+	if isBulkAction(&action) {
+		s.processBulkAction(&action)
+		return
+	}
 
 	var statusStr string
 	var errExec error
@@ -128,10 +193,105 @@ func processAction(msg *kafka.Message) {
 		logger.Log.Info("Action executed successfully")
 	}
 
-	publishStatus(&action, statusStr, errExec)
+	s.publishStatus(&action, statusStr, errExec)
+}
+
+// isBulkAction reports whether action targets a fleet rather than a single
+// resource: either an explicit comma-separated target list or a label
+// selector was supplied instead of (or in addition to) action.Target.
+func isBulkAction(action *events.Action) bool {
+	_, hasSelector := action.Params["selector"]
+	_, hasTargets := action.Params["targets"]
+	return hasSelector || hasTargets
+}
+
+// processBulkAction fans action out across every resolved target via
+// executor.ExecuteBulk and publishes one aggregated events.action_completed
+// summary instead of a status per target.
+func (s *ExecutorService) processBulkAction(action *events.Action) {
+	var targets []string
+	if raw, ok := action.Params["targets"]; ok {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				targets = append(targets, t)
+			}
+		}
+	}
+
+	timeout := 5 * time.Minute
+	if v, ok := action.Params["timeout"]; ok {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			timeout = time.Duration(secs) * time.Second
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := executor.ExecuteBulk(ctx, action.ActionType, targets, action.Params)
+	s.publishBulkCompletion(action, results)
+}
+
+// targetOutcome is the JSON-friendly form of executor.TargetResult; an
+// error interface doesn't marshal usefully on its own.
+type targetOutcome struct {
+	Target             string    `json:"target"`
+	Started            time.Time `json:"started"`
+	Finished           time.Time `json:"finished"`
+	Error              string    `json:"error,omitempty"`
+	Classification     string    `json:"classification,omitempty"`
+	ObservedGeneration int64     `json:"observed_generation"`
+}
+
+func (s *ExecutorService) publishBulkCompletion(action *events.Action, results []executor.TargetResult) {
+	outcomes := make([]targetOutcome, len(results))
+	failed := 0
+	for i, r := range results {
+		outcomes[i] = targetOutcome{
+			Target:             r.Target,
+			Started:            r.Started,
+			Finished:           r.Finished,
+			ObservedGeneration: r.ObservedGeneration,
+		}
+		if r.Err != nil {
+			outcomes[i].Error = r.Err.Error()
+			outcomes[i].Classification = executor.ClassificationLabel(r.Err)
+			failed++
+		}
+	}
+
+	summary := struct {
+		ActionID   string          `json:"action_id"`
+		ActionType string          `json:"action_type"`
+		Succeeded  int             `json:"succeeded"`
+		Failed     int             `json:"failed"`
+		Results    []targetOutcome `json:"results"`
+	}{
+		ActionID:   action.ActionId,
+		ActionType: action.ActionType,
+		Succeeded:  len(results) - failed,
+		Failed:     failed,
+		Results:    outcomes,
+	}
+
+	val, err := json.Marshal(summary)
+	if err != nil {
+		logger.Log.Error("Failed to marshal bulk action summary", zap.Error(err))
+		return
+	}
+
+	if err := s.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &actionCompletedTopic, Partition: kafka.PartitionAny},
+		Value:          val,
+	}, nil); err != nil {
+		logger.Log.Error("Failed to publish bulk action completion", zap.Error(err))
+		return
+	}
+	logger.Log.Info("Bulk action completed",
+		zap.String("action_id", action.ActionId), zap.Int("succeeded", summary.Succeeded), zap.Int("failed", failed))
 }
 
-func publishStatus(action *events.Action, statusStr string, errExec error) {
+func (s *ExecutorService) publishStatus(action *events.Action, statusStr string, errExec error) {
 	errMsg := ""
 	if errExec != nil {
 		errMsg = errExec.Error()
@@ -139,6 +299,7 @@ func publishStatus(action *events.Action, statusStr string, errExec error) {
 
 	status := &events.ActionStatus{
 		ActionId:  action.ActionId,
+		EventId:   action.EventId,
 		Status:    statusStr,
 		Error:     errMsg,
 		Timestamp: timestamppb.New(time.Now()),
@@ -150,7 +311,7 @@ func publishStatus(action *events.Action, statusStr string, errExec error) {
 		return
 	}
 
-	producer.Produce(&kafka.Message{
+	s.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &outputTopic, Partition: kafka.PartitionAny},
 		Value:          val,
 	}, nil)
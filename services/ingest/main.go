@@ -1,14 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/dedupe"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/metrics"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/runtime"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -18,48 +25,146 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-var producer *kafka.Producer
-var topic = "events.normalized"
+var (
+	topic           = "events.normalized"
+	dedupedTopic    = "events.deduplicated"
+	correlatedTopic = "events.correlated"
+)
+
+// IngestService owns the Kafka producer and alert-storm state for the Event
+// Ingest Service. It replaces the old package-level producer variable so
+// shutdown can flush a specific instance instead of reaching for ambient
+// state.
+type IngestService struct {
+	producer   *kafka.Producer
+	dedup      dedupe.Store
+	correlator *dedupe.Correlator
+}
 
 func main() {
 	logger.InitLogger()
 	logger.Log.Info("Starting Event Ingest Service...")
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	kafkaBroker := os.Getenv("KAFKA_BROKER")
 	if kafkaBroker == "" {
 		kafkaBroker = "localhost:9092"
 	}
 
-	var err error
-	producer, err = kafka.NewProducer(&kafka.ConfigMap{
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{
 		"bootstrap.servers": kafkaBroker,
 	})
 	if err != nil {
 		logger.Log.Fatal("Failed to create Kafka producer", zap.Error(err))
 	}
-	defer producer.Close()
 
-	http.HandleFunc("/ingest", ingestHandler)
-	http.HandleFunc("/health", healthHandler)
-	http.Handle("/metrics", promhttp.Handler())
+	dedupStore := dedupe.NewMemoryStore(envInt("DEDUPE_CACHE_SIZE", 10000), envDuration("DEDUPE_TTL_SECONDS", 5*time.Minute), func(fingerprint string) {
+		metrics.DedupeCacheEvictions.Inc()
+	})
+	stopSweep := dedupStore.StartSweeper(time.Minute)
+	defer stopSweep()
+
+	correlator := dedupe.NewCorrelator(envInt("CORRELATION_THRESHOLD", 5), envDuration("CORRELATION_WINDOW_SECONDS", 60*time.Second))
+
+	svc := &IngestService{producer: producer, dedup: dedupStore, correlator: correlator}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ingest", svc.ingestHandler)
+	mux.HandleFunc("/health", healthHandler)
+	mux.Handle("/metrics", promhttp.Handler())
 
+	server := runtime.NewServer(":8080", mux)
 	logger.Log.Info("Server listening on :8080 (metrics on /metrics)")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		logger.Log.Fatal("Server failed", zap.Error(err))
+	if err := server.Run(ctx); err != nil {
+		logger.Log.Error("Server failed", zap.Error(err))
 	}
+
+	logger.Log.Info("Shutting down, flushing producer...")
+	if unflushed := svc.producer.Flush(10000); unflushed > 0 {
+		logger.Log.Warn("Producer flush timed out with messages still queued", zap.Int("unflushed", unflushed))
+	}
+	svc.producer.Close()
 }
 
-// recordMetrics helper to record event metrics
+// recordEventMetrics helper to record event metrics
 func recordEventMetrics(severity string) {
 	metrics.EventsReceived.WithLabelValues("ingest", severity).Inc()
 }
 
+// envInt reads name as an int, falling back to def if unset or invalid.
+func envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// envDuration reads name as a count of seconds, falling back to def if
+// unset or invalid.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// extractAlertFields pulls the fields that identify "the same underlying
+// incident" out of an AlertManager-shaped payload, for fingerprinting.
+func extractAlertFields(payload map[string]interface{}) (alertName, service, namespace, severity string) {
+	severity = "info"
+
+	labels, _ := payload["commonLabels"].(map[string]interface{})
+	if labels == nil {
+		labels, _ = payload["labels"].(map[string]interface{})
+	}
+	if labels != nil {
+		if v, ok := labels["alertname"].(string); ok {
+			alertName = v
+		}
+		if v, ok := labels["service"].(string); ok {
+			service = v
+		} else if v, ok := labels["pod"].(string); ok {
+			service = v
+		}
+		if v, ok := labels["namespace"].(string); ok {
+			namespace = v
+		}
+		if v, ok := labels["severity"].(string); ok {
+			severity = v
+		}
+	}
+
+	if service == "" {
+		if v, ok := payload["service_name"].(string); ok {
+			service = v
+		} else if v, ok := payload["service"].(string); ok {
+			service = v
+		}
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	return alertName, service, namespace, severity
+}
+
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
 
-func ingestHandler(w http.ResponseWriter, r *http.Request) {
+func (s *IngestService) ingestHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -83,12 +188,34 @@ func ingestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	alertName, service, namespace, severity := extractAlertFields(rawPayload)
+	now := time.Now()
+	fp := dedupe.FP("http_ingest", alertName, service, namespace, severity)
+
+	entry, hit := s.dedup.Touch(fp, dedupe.Entry{
+		EventID:     uuid.New().String(),
+		RepeatCount: 1,
+		FirstSeen:   now,
+		LastSeen:    now,
+	})
+	if hit {
+		metrics.DedupeHits.Inc()
+		s.publishHeartbeat(entry, fp)
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"status": "deduplicated", "event_id": entry.EventID})
+		return
+	}
+
+	if group, ok := s.correlator.Observe(service, fp, entry.EventID, now); ok {
+		s.publishCorrelated(group)
+	}
+
 	// Normalize
 	normalized := &events.NormalizedEvent{
-		EventId:    uuid.New().String(),
+		EventId:    entry.EventID,
 		Source:     "http_ingest",
-		Timestamp:  timestamppb.New(time.Now()),
-		Severity:   "info",
+		Timestamp:  timestamppb.New(now),
+		Severity:   severity,
 		RawPayload: structPayload,
 		Metadata:   map[string]string{"received_by": "ingest-service"},
 	}
@@ -101,7 +228,7 @@ func ingestHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = producer.Produce(&kafka.Message{
+	err = s.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
 		Value:          msgBytes,
 	}, nil)
@@ -115,3 +242,82 @@ func ingestHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]string{"status": "queued", "event_id": normalized.EventId})
 }
+
+// publishHeartbeat publishes a lightweight events.deduplicated message in
+// place of a fresh events.normalized event, so downstream services see that
+// the incident is still firing without re-processing it as new.
+func (s *IngestService) publishHeartbeat(entry dedupe.Entry, fingerprint string) {
+	heartbeat := &events.NormalizedEvent{
+		EventId:   entry.EventID,
+		Source:    "dedupe",
+		Timestamp: timestamppb.New(entry.LastSeen),
+		Severity:  "info",
+		Metadata: map[string]string{
+			"fingerprint":  fingerprint,
+			"repeat_count": fmt.Sprintf("%d", entry.RepeatCount),
+		},
+	}
+
+	val, err := protojson.Marshal(heartbeat)
+	if err != nil {
+		logger.Log.Error("Failed to marshal dedupe heartbeat", zap.Error(err))
+		return
+	}
+
+	if err := s.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &dedupedTopic, Partition: kafka.PartitionAny},
+		Value:          val,
+	}, nil); err != nil {
+		logger.Log.Error("Failed to publish dedupe heartbeat", zap.Error(err))
+	}
+}
+
+// publishCorrelated publishes a synthetic events.correlated message
+// carrying the child event IDs of an alert storm, so the decision engine
+// can reason about the storm as a whole rather than its individual pings.
+func (s *IngestService) publishCorrelated(group dedupe.CorrelationGroup) {
+	metrics.CorrelationGroupsFormed.Inc()
+
+	childIDs := make([]interface{}, len(group.EventIDs))
+	for i, id := range group.EventIDs {
+		childIDs[i] = id
+	}
+
+	payload, err := structpb.NewStruct(map[string]interface{}{
+		"service_name":    group.ServiceName,
+		"child_event_ids": childIDs,
+		"window_start":    group.WindowStart.Format(time.RFC3339),
+		"window_end":      group.WindowEnd.Format(time.RFC3339),
+	})
+	if err != nil {
+		logger.Log.Error("Failed to build correlated payload", zap.Error(err))
+		return
+	}
+
+	correlated := &events.NormalizedEvent{
+		EventId:    uuid.New().String(),
+		Source:     "correlation",
+		Timestamp:  timestamppb.New(group.WindowEnd),
+		Severity:   "warning",
+		RawPayload: payload,
+		Metadata: map[string]string{
+			"correlated_service": group.ServiceName,
+			"child_count":        fmt.Sprintf("%d", len(group.EventIDs)),
+		},
+	}
+
+	val, err := protojson.Marshal(correlated)
+	if err != nil {
+		logger.Log.Error("Failed to marshal correlated event", zap.Error(err))
+		return
+	}
+
+	if err := s.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &correlatedTopic, Partition: kafka.PartitionAny},
+		Value:          val,
+	}, nil); err != nil {
+		logger.Log.Error("Failed to publish correlated event", zap.Error(err))
+	} else {
+		logger.Log.Info("Correlated alert storm", zap.String("service", group.ServiceName), zap.Int("count", len(group.EventIDs)))
+	}
+}
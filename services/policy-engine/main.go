@@ -1,36 +1,87 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/bot"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/metrics"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/policycrd"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/runtime"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/services/policy-engine/policy"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/homedir"
 )
 
 var (
-	inputTopics = []string{"decisions.k8s", "decisions.infra", "decisions.db"}
-	outputTopic = "actions.approved"
-	producer    *kafka.Producer
+	inputTopics          = []string{"decisions.k8s", "decisions.infra", "decisions.db"}
+	outputTopic          = "actions.approved"
+	pendingApprovalTopic = "actions.pending_approval"
 )
 
+// PolicyEngineService owns the Kafka clients and chat notifiers for the
+// Policy Engine. It replaces the old package-level producer/notifiers
+// globals so shutdown can drain and flush a specific instance.
+type PolicyEngineService struct {
+	producer  *kafka.Producer
+	consumer  *kafka.Consumer
+	notifiers []bot.Bot
+}
+
+// buildNotifiers constructs the configured chat adapters from environment
+// variables. A platform is only wired up if its webhook URL is set, so a
+// deployment can enable just one or all three.
+func buildNotifiers() []bot.Bot {
+	var bots []bot.Bot
+	if url := os.Getenv("SLACK_WEBHOOK_URL"); url != "" {
+		bots = append(bots, bot.NewSlackBot(url))
+	}
+	if url := os.Getenv("DISCORD_WEBHOOK_URL"); url != "" {
+		bots = append(bots, bot.NewDiscordBot(url))
+	}
+	if url := os.Getenv("TEAMS_WEBHOOK_URL"); url != "" {
+		bots = append(bots, bot.NewTeamsBot(url))
+	}
+	return bots
+}
+
 func main() {
 	logger.InitLogger()
 	logger.Log.Info("Starting Policy Engine Service...")
 
-	// Start metrics server on port 9090
-	go func() {
-		http.Handle("/metrics", promhttp.Handler())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	notifiers := buildNotifiers()
+	logger.Log.Info("Approval notifiers configured", zap.Int("count", len(notifiers)))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/policies/test", policiesTestHandler)
+	metricsServer := runtime.NewServer(":9090", mux)
+	runtime.HandleCrash("policy-engine-metrics-server", func() {
 		logger.Log.Info("Metrics server listening on :9090")
-		http.ListenAndServe(":9090", nil)
-	}()
+		if err := metricsServer.Run(ctx); err != nil {
+			logger.Log.Error("Metrics server failed", zap.Error(err))
+		}
+	})
+
+	startCRDWatcher(ctx)
 
 	kafkaBroker := os.Getenv("KAFKA_BROKER")
 
@@ -38,12 +89,10 @@ func main() {
 		kafkaBroker = "localhost:9092"
 	}
 
-	var err error
-	producer, err = kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": kafkaBroker})
+	producer, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": kafkaBroker})
 	if err != nil {
 		logger.Log.Fatal("Failed to create producer", zap.Error(err))
 	}
-	defer producer.Close()
 
 	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers": kafkaBroker,
@@ -53,28 +102,33 @@ func main() {
 	if err != nil {
 		logger.Log.Fatal("Failed to create consumer", zap.Error(err))
 	}
-	defer consumer.Close()
 
-	consumer.SubscribeTopics(inputTopics, nil)
+	svc := &PolicyEngineService{producer: producer, consumer: consumer, notifiers: notifiers}
+	if err := consumer.SubscribeTopics(inputTopics, nil); err != nil {
+		logger.Log.Fatal("Failed to subscribe to input topics", zap.Error(err))
+	}
 
-	sigchan := make(chan os.Signal, 1)
-	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+	svc.run(ctx)
+	svc.shutdown()
+}
 
-	run := true
-	for run {
+// run polls for decisions until ctx is cancelled, at which point it returns
+// so main can drain and flush.
+func (s *PolicyEngineService) run(ctx context.Context) {
+	for {
 		select {
-		case sig := <-sigchan:
-			logger.Log.Info("Caught signal, terminating", zap.String("signal", sig.String()))
-			run = false
+		case <-ctx.Done():
+			logger.Log.Info("Context cancelled, stopping poll loop")
+			return
 		default:
-			ev := consumer.Poll(100)
+			ev := s.consumer.Poll(100)
 			if ev == nil {
 				continue
 			}
 
 			switch e := ev.(type) {
 			case *kafka.Message:
-				processDecision(e)
+				s.processDecision(e)
 			case kafka.Error:
 				logger.Log.Error("Kafka error", zap.Error(e))
 			}
@@ -82,7 +136,85 @@ func main() {
 	}
 }
 
-func processDecision(msg *kafka.Message) {
+// shutdown flushes any in-flight publishes before closing the Kafka
+// clients, so a SIGTERM doesn't lose an approval or pending-approval message.
+func (s *PolicyEngineService) shutdown() {
+	logger.Log.Info("Shutting down, flushing producer...")
+	if unflushed := s.producer.Flush(10000); unflushed > 0 {
+		logger.Log.Warn("Producer flush timed out with messages still queued", zap.Int("unflushed", unflushed))
+	}
+	s.consumer.Close()
+	s.producer.Close()
+}
+
+// startCRDWatcher wires the policy package's Evaluate up to live
+// RemediationPolicy updates. It's best-effort: if no kubeconfig is
+// available (e.g. local dev without a cluster), the engine just keeps
+// running on its built-in default policy.
+func startCRDWatcher(ctx context.Context) {
+	var kubeconfig string
+	if home := homedir.HomeDir(); home != "" {
+		kubeconfig = filepath.Join(home, ".kube", "config")
+	} else {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			logger.Log.Warn("No kubeconfig available, RemediationPolicy hot-reload disabled", zap.Error(err))
+			return
+		}
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		logger.Log.Warn("Failed to build dynamic client, RemediationPolicy hot-reload disabled", zap.Error(err))
+		return
+	}
+
+	namespace := os.Getenv("POLICY_CRD_NAMESPACE")
+	clientset := policycrd.NewClientset(dynamicClient)
+
+	runtime.HandleCrash("remediation-policy-watcher", func() {
+		if err := policy.StartCRDWatcher(ctx, clientset, namespace); err != nil && ctx.Err() == nil {
+			logger.Log.Warn("RemediationPolicy watcher stopped", zap.Error(err))
+		}
+	})
+}
+
+// policiesTestHandler evaluates a candidate action against the current
+// policy set without side effects, so operators can test a policy change
+// offline before rolling it out.
+func policiesTestHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var action events.Action
+	if err := protojson.Unmarshal(body, &action); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	decision, reason := policy.Test(&action)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"decision": string(decision),
+		"reason":   reason,
+	})
+}
+
+func (s *PolicyEngineService) processDecision(msg *kafka.Message) {
 	var decision events.Decision
 	if err := protojson.Unmarshal(msg.Value, &decision); err != nil {
 		logger.Log.Error("Failed to unmarshal decision", zap.Error(err))
@@ -92,23 +224,78 @@ func processDecision(msg *kafka.Message) {
 	logger.Log.Info("Processing decision", zap.String("decision_id", decision.DecisionId))
 
 	for _, action := range decision.ProposedActions {
-		if allowed, reason := policy.Evaluate(action); allowed {
+		// Stamp the originating event_id onto the action so downstream
+		// consumers (executor's actions.status, e2e verification) can
+		// correlate a remediation all the way back to the alert that
+		// triggered it, not just to this decision.
+		if action.EventId == "" {
+			action.EventId = decision.EventId
+		}
+
+		result, reason := policy.Evaluate(action)
+		metrics.PolicyDecisions.WithLabelValues(string(result)).Inc()
+
+		switch result {
+		case policy.DecisionAllow:
 			action.Approver = "policy_engine_auto"
-			approveAction(action)
-		} else {
+			s.approveAction(action)
+		case policy.DecisionPendingApproval:
+			s.sendForApproval(action, reason)
+		default:
 			logger.Log.Warn("Action rejected", zap.String("action_id", action.ActionId), zap.String("reason", reason))
 		}
 	}
 }
 
-func approveAction(action *events.Action) {
+// sendForApproval publishes the action to actions.pending_approval so
+// services/approval-gateway can pick it up once an approver responds, and
+// delivers an interactive prompt to every configured chat bot so a human
+// sees it right away.
+func (s *PolicyEngineService) sendForApproval(action *events.Action, reason string) {
+	logger.Log.Info("Action requires human approval",
+		zap.String("action_id", action.ActionId), zap.String("reason", reason))
+	metrics.ApprovalsPending.Inc()
+
+	val, err := protojson.Marshal(action)
+	if err != nil {
+		logger.Log.Error("Failed to marshal pending-approval action", zap.Error(err))
+		return
+	}
+
+	err = s.producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &pendingApprovalTopic, Partition: kafka.PartitionAny},
+		Value:          val,
+	}, nil)
+	if err != nil {
+		logger.Log.Error("Failed to publish pending-approval action", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := bot.ApprovalRequest{
+		ActionID:    action.ActionId,
+		ActionType:  action.ActionType,
+		Target:      action.Target,
+		Reason:      reason,
+		RequestedAt: time.Now(),
+	}
+	for _, n := range s.notifiers {
+		if err := n.PostApprovalPrompt(ctx, req); err != nil {
+			logger.Log.Warn("Failed to post approval prompt", zap.String("bot", n.Name()), zap.Error(err))
+		}
+	}
+}
+
+func (s *PolicyEngineService) approveAction(action *events.Action) {
 	val, err := protojson.Marshal(action)
 	if err != nil {
 		logger.Log.Error("Failed to marshal approved action", zap.Error(err))
 		return
 	}
 
-	err = producer.Produce(&kafka.Message{
+	err = s.producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &outputTopic, Partition: kafka.PartitionAny},
 		Value:          val,
 	}, nil)
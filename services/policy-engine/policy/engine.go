@@ -1,31 +1,16 @@
 package policy
 
 import (
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/metrics"
 )
 
-// AllowList of permitted action types
-var allowedActions = map[string]bool{
-	"restart_pod":                true,
-	"scale_deployment":           true,
-	"rolling_restart_deployment": true,
-	"gather_logs":                true,
-	"flush_cache":                true,
-}
-
-// Namespace Restrictions: only these namespaces can be targeted
-var allowedNamespaces = map[string]bool{
-	"default": true,
-	"apps":    true,
-	"staging": true,
-	// "production": false, // Requires human approval (not in list)
-}
-
-// Rate Limiting: Track actions per target
+// Rate Limiting: Track actions per target. The allowed-actions/namespace
+// rules now live in Rego (see rego.go); rate limiting stays in Go because it
+// is stateful and Rego policies are meant to be pure functions of input+data.
 var (
 	rateLimitMu         sync.Mutex
 	actionHistory       = make(map[string][]time.Time) // key: "action_type:target", value: timestamps
@@ -33,40 +18,61 @@ var (
 	rateLimitWindow     = 1 * time.Hour
 )
 
-func Evaluate(action *events.Action) (bool, string) {
-	// 1. AllowList Check
-	if !allowedActions[action.ActionType] {
-		return false, "Action type '" + action.ActionType + "' is not in the AllowList"
-	}
-
-	// 2. Forbidden Keywords (Delete)
-	if strings.Contains(strings.ToLower(action.ActionType), "delete") {
-		return false, "Automatic deletion is forbidden"
-	}
+// Decision is the outcome of evaluating an action against policy.
+type Decision string
+
+const (
+	// DecisionAllow means the action can proceed immediately.
+	DecisionAllow Decision = "allow"
+	// DecisionDeny means the action is never permitted, regardless of approval.
+	DecisionDeny Decision = "deny"
+	// DecisionPendingApproval means the action is plausible but needs a human
+	// sign-off before it can proceed (production namespace, rate limit, or an
+	// action type the AllowList doesn't recognize).
+	DecisionPendingApproval Decision = "pending_approval"
+)
 
-	// 3. Namespace Restrictions
+// Evaluate is a thin wrapper that assembles the Rego input document for
+// action, evaluates the compiled `data.remediation` query, and folds its
+// hard_deny/soft_deny rule sets together with the (stateful) rate-limit check into a single
+// Decision. It never panics: any Rego evaluation error fails closed as
+// DecisionDeny. Every path increments PolicyRuleDecisions under the name of
+// whichever rule decided the outcome, so operators can see which rule is
+// firing instead of just the aggregate allow/deny/pending_approval split.
+func Evaluate(action *events.Action) (Decision, string) {
 	namespace := action.Params["namespace"]
 	if namespace == "" {
 		namespace = "default" // Assume default if not specified
 	}
 
-	// Always block kube-system
-	if namespace == "kube-system" {
-		return false, "Cannot perform actions in kube-system namespace"
+	result, err := decide(action, namespace, time.Now())
+	if err != nil {
+		metrics.PolicyRuleDecisions.WithLabelValues("policy_error", string(DecisionDeny)).Inc()
+		return DecisionDeny, "policy evaluation error: " + err.Error()
 	}
 
-	// Check if namespace is in allowed list
-	if !allowedNamespaces[namespace] {
-		return false, "Namespace '" + namespace + "' requires human approval"
+	if hd, ok := result.firstHardDeny(); ok {
+		metrics.PolicyRuleDecisions.WithLabelValues(hd.Rule, string(DecisionDeny)).Inc()
+		return DecisionDeny, hd.Reason
+	}
+	if sd, ok := result.firstSoftDeny(); ok {
+		metrics.PolicyRuleDecisions.WithLabelValues(sd.Rule, string(DecisionPendingApproval)).Inc()
+		return DecisionPendingApproval, sd.Reason
 	}
 
-	// 4. Rate Limiting
 	key := action.ActionType + ":" + action.Target
 	if !checkRateLimit(key) {
-		return false, "Rate limit exceeded: Too many '" + action.ActionType + "' actions on '" + action.Target + "' in the last hour"
+		metrics.PolicyRuleDecisions.WithLabelValues("rate_limit", string(DecisionPendingApproval)).Inc()
+		return DecisionPendingApproval, "Rate limit exceeded: Too many '" + action.ActionType + "' actions on '" + action.Target + "' in the last hour"
+	}
+
+	if isDryRun() {
+		metrics.PolicyRuleDecisions.WithLabelValues("dry_run", string(DecisionPendingApproval)).Inc()
+		return DecisionPendingApproval, "Dry-run mode: action would have been allowed, routing to approval instead of executing"
 	}
 
-	return true, "Policy check passed"
+	metrics.PolicyRuleDecisions.WithLabelValues("allow", string(DecisionAllow)).Inc()
+	return DecisionAllow, "Policy check passed"
 }
 
 func checkRateLimit(key string) bool {
@@ -0,0 +1,241 @@
+package policy
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/policycrd"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"go.uber.org/zap"
+)
+
+//go:embed rego/*.rego
+var regoFS embed.FS
+
+var (
+	preparedMu sync.RWMutex
+	prepared   rego.PreparedEvalQuery
+
+	dryRun atomic.Bool
+)
+
+func init() {
+	if err := reload(defaultPolicyData()); err != nil {
+		// The Rego module is embedded in the binary, so a compile failure
+		// here means a broken build, not bad runtime data.
+		logger.Log.Fatal("Failed to compile default remediation policy", zap.Error(err))
+	}
+}
+
+// defaultPolicyData seeds the engine with the same rules the old hard-coded
+// Go maps enforced, so behavior is unchanged until a RemediationPolicy CRD
+// is observed.
+func defaultPolicyData() map[string]interface{} {
+	return map[string]interface{}{
+		"allowed_actions":    []string{"restart_pod", "scale_deployment", "rolling_restart_deployment", "gather_logs", "flush_cache"},
+		"allowed_namespaces": []string{"default", "apps", "staging"},
+	}
+}
+
+// ruleReason names the Rego rule that fired alongside its human-readable
+// reason, so callers can segment metrics by rule rather than just decision.
+type ruleReason struct {
+	Rule   string
+	Reason string
+}
+
+// decisionResult is the decoded `data.remediation` document for one
+// evaluation: the sets of rule/reason pairs that would hard-deny or
+// soft-deny (route to approval) the action.
+type decisionResult struct {
+	HardDeny []ruleReason
+	SoftDeny []ruleReason
+}
+
+func (r decisionResult) firstHardDeny() (ruleReason, bool) {
+	if len(r.HardDeny) == 0 {
+		return ruleReason{}, false
+	}
+	return r.HardDeny[0], true
+}
+
+func (r decisionResult) firstSoftDeny() (ruleReason, bool) {
+	if len(r.SoftDeny) == 0 {
+		return ruleReason{}, false
+	}
+	return r.SoftDeny[0], true
+}
+
+// decide assembles the Rego input document and evaluates the compiled
+// query against it.
+func decide(action *events.Action, namespace string, now time.Time) (decisionResult, error) {
+	input := map[string]interface{}{
+		"action": map[string]interface{}{
+			"action_type": action.ActionType,
+			"target":      action.Target,
+			"params":      action.Params,
+		},
+		"decision_context": map[string]interface{}{
+			"namespace": namespace,
+		},
+		"cluster_id": action.Params["cluster_id"],
+		"time":       now.Format(time.RFC3339),
+	}
+
+	preparedMu.RLock()
+	pq := prepared
+	preparedMu.RUnlock()
+
+	rs, err := pq.Eval(context.Background(), rego.EvalInput(input))
+	if err != nil {
+		return decisionResult{}, fmt.Errorf("rego eval: %w", err)
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return decisionResult{}, fmt.Errorf("rego eval produced no result")
+	}
+
+	doc, ok := rs[0].Expressions[0].Value.(map[string]interface{})
+	if !ok {
+		return decisionResult{}, fmt.Errorf("unexpected rego result shape: %T", rs[0].Expressions[0].Value)
+	}
+
+	return decisionResult{
+		HardDeny: toRuleReasons(doc["hard_deny"]),
+		SoftDeny: toRuleReasons(doc["soft_deny"]),
+	}, nil
+}
+
+// toRuleReasons decodes a Rego set of {"rule": ..., "reason": ...} objects
+// into ruleReasons, skipping anything that doesn't match that shape.
+func toRuleReasons(v interface{}) []ruleReason {
+	raw, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]ruleReason, 0, len(raw))
+	for _, item := range raw {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rule, _ := obj["rule"].(string)
+		reason, _ := obj["reason"].(string)
+		out = append(out, ruleReason{Rule: rule, Reason: reason})
+	}
+	return out
+}
+
+// reload recompiles the Rego query against the given policy data (typically
+// sourced from a RemediationPolicy CRD). The Rego *logic* is always the
+// module embedded in the binary; only the *data* it runs against changes.
+// Safe to call concurrently with Evaluate; the swap is atomic from the
+// caller's perspective.
+func reload(data map[string]interface{}) error {
+	entries, err := regoFS.ReadDir("rego")
+	if err != nil {
+		return fmt.Errorf("read embedded rego modules: %w", err)
+	}
+
+	opts := []func(*rego.Rego){
+		rego.Query("data.remediation"),
+		rego.Store(inmem.NewFromObject(data)),
+	}
+	for _, entry := range entries {
+		content, err := regoFS.ReadFile("rego/" + entry.Name())
+		if err != nil {
+			return fmt.Errorf("read embedded rego module %s: %w", entry.Name(), err)
+		}
+		opts = append(opts, rego.Module(entry.Name(), string(content)))
+	}
+
+	pq, err := rego.New(opts...).PrepareForEval(context.Background())
+	if err != nil {
+		return fmt.Errorf("compile rego modules: %w", err)
+	}
+
+	preparedMu.Lock()
+	prepared = pq
+	preparedMu.Unlock()
+	return nil
+}
+
+func isDryRun() bool {
+	return dryRun.Load()
+}
+
+// ApplyCRD converts a RemediationPolicy CRD into Rego policy data and
+// recompiles the query, implementing hot-reload: cluster operators change
+// the CRD, the policy engine's next Evaluate call sees the new rules.
+func ApplyCRD(policy *policycrd.RemediationPolicy) {
+	if policy == nil {
+		// The CRD was deleted; fall back to the built-in defaults rather
+		// than leaving the engine with no policy at all.
+		dryRun.Store(false)
+		if err := reload(defaultPolicyData()); err != nil {
+			logger.Log.Error("Failed to reload default policy after CRD deletion", zap.Error(err))
+		}
+		return
+	}
+
+	data := map[string]interface{}{
+		"allowed_actions":    policy.Spec.AllowedActions,
+		"allowed_namespaces": policy.Spec.AllowedNamespaces,
+		"forbidden_keywords": policy.Spec.ForbiddenKeywords,
+	}
+	for _, rl := range policy.Spec.RateLimits {
+		if rl.ActionType == "" {
+			continue
+		}
+		rateLimitMu.Lock()
+		maxActionsPerTarget = rl.MaxPerHour
+		rateLimitMu.Unlock()
+		break // the hard-coded limiter is global; first rule wins until per-action limits land
+	}
+
+	dryRun.Store(policy.Spec.DryRun)
+
+	if err := reload(data); err != nil {
+		logger.Log.Error("Failed to reload policy from RemediationPolicy CRD, keeping previous policy",
+			zap.String("name", policy.Name), zap.Error(err))
+		return
+	}
+	logger.Log.Info("Policy reloaded from RemediationPolicy CRD",
+		zap.String("name", policy.Name), zap.Bool("dry_run", policy.Spec.DryRun))
+}
+
+// StartCRDWatcher watches RemediationPolicy objects and hot-reloads the
+// policy on every change. It blocks until ctx is cancelled, so callers
+// should run it in a goroutine.
+func StartCRDWatcher(ctx context.Context, clientset *policycrd.Clientset, namespace string) error {
+	return clientset.WatchAndReload(ctx, namespace, ApplyCRD)
+}
+
+// Test evaluates a candidate action against the current policy set without
+// any side effects (no rate-limit bookkeeping, no dry-run override),
+// backing the POST /policies/test endpoint so operators can check a policy
+// change offline before rolling it out.
+func Test(action *events.Action) (Decision, string) {
+	namespace := action.Params["namespace"]
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	result, err := decide(action, namespace, time.Now())
+	if err != nil {
+		return DecisionDeny, "policy evaluation error: " + err.Error()
+	}
+	if hd, ok := result.firstHardDeny(); ok {
+		return DecisionDeny, hd.Reason
+	}
+	if sd, ok := result.firstSoftDeny(); ok {
+		return DecisionPendingApproval, sd.Reason
+	}
+	return DecisionAllow, "Policy check passed"
+}
@@ -0,0 +1,217 @@
+package enrich
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/metrics"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// byAppLabelIndex indexes Deployments, ReplicaSets, StatefulSets, Pods, and
+// Services by their "app" label, so Enrich can do an O(1) lookup instead of
+// a List call to the API server on every event.
+const byAppLabelIndex = "byAppLabel"
+
+// namespaceInformers bundles the informers watched for a single namespace.
+type namespaceInformers struct {
+	namespace    string
+	deployments  cache.SharedIndexInformer
+	replicaSets  cache.SharedIndexInformer
+	statefulSets cache.SharedIndexInformer
+	pods         cache.SharedIndexInformer
+	services     cache.SharedIndexInformer
+}
+
+// resourceCache is the Enrich-side read model built from client-go
+// informers; it replaces the per-event Deployments().List()/Pods().List()
+// calls the naive implementation made.
+type resourceCache struct {
+	factories []informers.SharedInformerFactory
+	synced    []cache.InformerSynced
+	byNS      []namespaceInformers
+}
+
+var resources *resourceCache
+
+// watchedNamespaces returns the namespaces to index, from the
+// WATCHED_NAMESPACES env var (comma-separated), defaulting to "default"
+// so behavior matches the old hard-coded namespace until configured.
+func watchedNamespaces() []string {
+	raw := os.Getenv("WATCHED_NAMESPACES")
+	if raw == "" {
+		return []string{"default"}
+	}
+	var out []string
+	for _, ns := range strings.Split(raw, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			out = append(out, ns)
+		}
+	}
+	return out
+}
+
+func indexByAppLabel(obj interface{}) ([]string, error) {
+	m, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("object %T has no ObjectMeta", obj)
+	}
+	if app := m.GetLabels()["app"]; app != "" {
+		return []string{app}, nil
+	}
+	return nil, nil
+}
+
+// startResourceCache builds one SharedInformerFactory per watched namespace,
+// registers the "app" label indexer on every informer, starts them, and
+// blocks until the initial list+watch sync completes.
+func startResourceCache(clientset kubernetes.Interface) *resourceCache {
+	stopCh := make(chan struct{})
+	rc := &resourceCache{}
+
+	for _, ns := range watchedNamespaces() {
+		factory := informers.NewSharedInformerFactoryWithOptions(clientset, 10*time.Minute, informers.WithNamespace(ns))
+
+		nsInformers := namespaceInformers{
+			namespace:    ns,
+			deployments:  factory.Apps().V1().Deployments().Informer(),
+			replicaSets:  factory.Apps().V1().ReplicaSets().Informer(),
+			statefulSets: factory.Apps().V1().StatefulSets().Informer(),
+			pods:         factory.Core().V1().Pods().Informer(),
+			services:     factory.Core().V1().Services().Informer(),
+		}
+
+		for _, informer := range []cache.SharedIndexInformer{
+			nsInformers.deployments, nsInformers.replicaSets, nsInformers.statefulSets,
+			nsInformers.pods, nsInformers.services,
+		} {
+			informer.AddIndexers(cache.Indexers{byAppLabelIndex: indexByAppLabel})
+			rc.synced = append(rc.synced, informer.HasSynced)
+		}
+
+		rc.factories = append(rc.factories, factory)
+		rc.byNS = append(rc.byNS, nsInformers)
+		factory.Start(stopCh)
+	}
+
+	if !cache.WaitForCacheSync(stopCh, rc.synced...) {
+		logger.Log.Warn("Enrichment informers failed to sync before timeout")
+	} else {
+		logger.Log.Info("Enrichment informer cache synced", zap.Strings("namespaces", watchedNamespaces()))
+	}
+
+	return rc
+}
+
+// Synced reports whether every registered informer has completed its
+// initial sync, backing the /healthz handler.
+func (rc *resourceCache) Synced() bool {
+	if rc == nil {
+		return false
+	}
+	for _, s := range rc.synced {
+		if !s() {
+			return false
+		}
+	}
+	return true
+}
+
+// relatedResources returns deployment/replicaset/pod/service/statefulset
+// identifiers related to serviceName, resolving an indexed "app" label match
+// first and falling back to owner-chain traversal when serviceName is
+// actually a pod name (e.g. "ingest-7c9d4f-abcde" from an AlertManager
+// "pod" label rather than the deployment name itself).
+func (rc *resourceCache) relatedResources(serviceName string) []string {
+	if rc == nil {
+		metrics.EnrichmentCacheLookups.WithLabelValues("miss").Inc()
+		return []string{}
+	}
+
+	var found []string
+	for _, ns := range rc.byNS {
+		found = append(found, byIndex(ns.deployments, serviceName, "deployment")...)
+		found = append(found, byIndex(ns.replicaSets, serviceName, "replicaset")...)
+		found = append(found, byIndex(ns.statefulSets, serviceName, "statefulset")...)
+		found = append(found, byIndex(ns.services, serviceName, "service")...)
+		found = append(found, byIndex(ns.pods, serviceName, "pod")...)
+	}
+
+	if len(found) == 0 {
+		// serviceName might be a pod name rather than an app label; walk its
+		// owner chain (Pod -> ReplicaSet -> Deployment, or Pod -> StatefulSet).
+		found = rc.resolveByOwnerChain(serviceName)
+	}
+
+	if len(found) == 0 {
+		metrics.EnrichmentCacheLookups.WithLabelValues("miss").Inc()
+		return []string{}
+	}
+
+	metrics.EnrichmentCacheLookups.WithLabelValues("hit").Inc()
+	return found
+}
+
+func byIndex(informer cache.SharedIndexInformer, appLabel, kind string) []string {
+	objs, err := informer.GetIndexer().ByIndex(byAppLabelIndex, appLabel)
+	if err != nil {
+		return nil
+	}
+
+	out := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		if m, ok := obj.(metav1.Object); ok {
+			out = append(out, kind+"/"+m.GetName())
+		}
+	}
+	return out
+}
+
+// resolveByOwnerChain treats name as a Pod name and walks Pod -> ReplicaSet
+// -> Deployment (or Pod -> StatefulSet) owner references to find the
+// workload it belongs to.
+func (rc *resourceCache) resolveByOwnerChain(name string) []string {
+	for _, ns := range rc.byNS {
+		podObj, exists, err := ns.pods.GetStore().GetByKey(ns.namespace + "/" + name)
+		if err != nil || !exists {
+			continue
+		}
+		pod, ok := podObj.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+
+		related := []string{"pod/" + pod.Name}
+		for _, owner := range pod.OwnerReferences {
+			switch owner.Kind {
+			case "ReplicaSet":
+				rsObj, exists, err := ns.replicaSets.GetStore().GetByKey(ns.namespace + "/" + owner.Name)
+				if err != nil || !exists {
+					continue
+				}
+				rs, ok := rsObj.(*appsv1.ReplicaSet)
+				if !ok {
+					continue
+				}
+				for _, rsOwner := range rs.OwnerReferences {
+					if rsOwner.Kind == "Deployment" {
+						related = append(related, "deployment/"+rsOwner.Name)
+					}
+				}
+			case "StatefulSet":
+				related = append(related, "statefulset/"+owner.Name)
+			}
+		}
+		return related
+	}
+	return nil
+}
@@ -1,14 +1,12 @@
 package enrich
 
 import (
-	"context"
 	"os"
 	"path/filepath"
 
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
 	"go.uber.org/zap"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -41,6 +39,14 @@ func InitK8sClient() {
 		return
 	}
 	logger.Log.Info("K8s client initialized for Router enrichment")
+
+	resources = startResourceCache(clientset)
+}
+
+// CacheSynced reports whether the enrichment informer cache has completed
+// its initial sync, for use by a /healthz handler.
+func CacheSynced() bool {
+	return resources.Synced()
 }
 
 func Enrich(ev *events.NormalizedEvent) *events.DomainEvent {
@@ -164,31 +170,12 @@ func contains(s string, substrs ...string) bool {
 	return false
 }
 
+// getRelatedResources looks up serviceName in the informer-backed cache
+// instead of issuing a List call to the API server on every event.
 func getRelatedResources(serviceName string) []string {
 	if clientset == nil || serviceName == "unknown-service" {
 		return []string{}
 	}
 
-	var resources []string
-	namespace := "default"
-
-	// Try to find deployment
-	deployments, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err == nil {
-		for _, dep := range deployments.Items {
-			if dep.Name == serviceName {
-				resources = append(resources, "deployment/"+dep.Name)
-				// Get pods for this deployment
-				pods, _ := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
-					LabelSelector: "app=" + serviceName,
-				})
-				for _, pod := range pods.Items {
-					resources = append(resources, "pod/"+pod.Name)
-				}
-				break
-			}
-		}
-	}
-
-	return resources
+	return resources.relatedResources(serviceName)
 }
@@ -1,31 +1,98 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"syscall"
+	"time"
 
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/dedupe"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/leaderelection"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/logger"
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/runtime"
 	"github.com/ChiragJS/IncidentResponseOrchestrator/services/router/enrich"
 	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/homedir"
 )
 
+// defaultDedupCacheSize bounds the idempotency cache when
+// ROUTER_DEDUP_CACHE_SIZE isn't set.
+const defaultDedupCacheSize = 10000
+
+// defaultDedupTTL bounds how long an EventId is remembered when
+// ROUTER_DEDUP_TTL_SECONDS isn't set; it just needs to outlast Kafka's
+// at-least-once redelivery window, not the lifetime of the incident.
+const defaultDedupTTL = 15 * time.Minute
+
+func dedupCacheSize() int {
+	v := os.Getenv("ROUTER_DEDUP_CACHE_SIZE")
+	if v == "" {
+		return defaultDedupCacheSize
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return defaultDedupCacheSize
+	}
+	return n
+}
+
+func dedupTTL() time.Duration {
+	v := os.Getenv("ROUTER_DEDUP_TTL_SECONDS")
+	if v == "" {
+		return defaultDedupTTL
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs <= 0 {
+		return defaultDedupTTL
+	}
+	return time.Duration(secs) * time.Second
+}
+
 var (
-	inputTopic = "events.normalized"
-	producer   *kafka.Producer
+	inputTopic      = "events.normalized"
+	deadletterTopic = "events.deadletter"
+	producer        *kafka.Producer
+	elector         *leaderelection.Elector
+	seen            *dedupe.MemoryStore
 )
 
 func main() {
 	logger.InitLogger()
 	logger.Log.Info("Starting Event Router Service...")
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// Initialize K8s client for enrichment
 	enrich.InitK8sClient()
 
+	seen = dedupe.NewMemoryStore(dedupCacheSize(), dedupTTL(), nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", healthzHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+	metricsServer := runtime.NewServer(":9090", mux)
+	runtime.HandleCrash("router-metrics-server", func() {
+		logger.Log.Info("Metrics server listening on :9090")
+		if err := metricsServer.Run(ctx); err != nil {
+			logger.Log.Error("Metrics server failed", zap.Error(err))
+		}
+	})
+
+	startLeaderElection(ctx)
+
 	kafkaBroker := os.Getenv("KAFKA_BROKER")
 	if kafkaBroker == "" {
 		kafkaBroker = "localhost:9092"
@@ -36,7 +103,7 @@ func main() {
 	if err != nil {
 		logger.Log.Fatal("Failed to create producer", zap.Error(err))
 	}
-	defer producer.Close()
+	runtime.HandleCrash("router-delivery-reports", func() { watchDeliveryReports(ctx) })
 
 	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers": kafkaBroker,
@@ -46,20 +113,29 @@ func main() {
 	if err != nil {
 		logger.Log.Fatal("Failed to create consumer", zap.Error(err))
 	}
-	defer consumer.Close()
 
 	consumer.SubscribeTopics([]string{inputTopic}, nil)
 
-	sigchan := make(chan os.Signal, 1)
-	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
-
-	run := true
-	for run {
+	for {
 		select {
-		case sig := <-sigchan:
-			logger.Log.Info("Caught signal, terminating", zap.String("signal", sig.String()))
-			run = false
+		case <-ctx.Done():
+			logger.Log.Info("Context cancelled, stopping poll loop")
+			consumer.Close()
+			logger.Log.Info("Shutting down, flushing producer...")
+			if unflushed := producer.Flush(10000); unflushed > 0 {
+				logger.Log.Warn("Producer flush timed out with messages still queued", zap.Int("unflushed", unflushed))
+			}
+			producer.Close()
+			return
 		default:
+			// Non-leaders stay connected to Kafka (so they're ready to take
+			// over instantly) but don't consume, to avoid double-scaling or
+			// double-restarting a target alongside the active leader.
+			if !elector.IsLeader() {
+				time.Sleep(500 * time.Millisecond)
+				continue
+			}
+
 			ev := consumer.Poll(100)
 			if ev == nil {
 				continue
@@ -75,35 +151,162 @@ func main() {
 	}
 }
 
+// startLeaderElection wires the router's poll loop up to a Lease in the
+// pod's own namespace, so only one replica actively consumes at a time. If
+// no kubeconfig is available (e.g. local dev without a cluster), the router
+// just runs as if it always holds the lease.
+func startLeaderElection(ctx context.Context) {
+	clientset, err := buildK8sClientset()
+	if err != nil {
+		logger.Log.Warn("No kubeconfig available, leader election disabled (running as singleton)", zap.Error(err))
+		return
+	}
+
+	cfg := leaderelection.ConfigFromEnv()
+	elector, err = leaderelection.Run(ctx, clientset, cfg, func() {
+		logger.Log.Info("Resuming consumption as leader")
+	}, func() {
+		logger.Log.Info("Pausing consumption, no longer leader")
+	})
+	if err != nil {
+		logger.Log.Warn("Failed to start leader election, running as singleton", zap.Error(err))
+	}
+}
+
+func buildK8sClientset() (*kubernetes.Clientset, error) {
+	var kubeconfig string
+	if home := homedir.HomeDir(); home != "" {
+		kubeconfig = filepath.Join(home, ".kube", "config")
+	} else {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		config, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
+// healthzHandler reports 200 once the enrichment informer cache has synced,
+// distinguishing "healthy standby" (200, not leading) from "unhealthy" (503)
+// so k8s probes don't restart a non-leader replica that's working as
+// intended.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	if !enrich.CacheSynced() {
+		http.Error(w, "enrichment cache not yet synced", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	if elector.IsLeader() {
+		w.Write([]byte("OK (leader)"))
+	} else {
+		w.Write([]byte("OK (standby)"))
+	}
+}
+
+// watchDeliveryReports drains producer.Events() in the background so a
+// failed Produce (e.g. to events.deadletter itself, or a routed domain
+// event) is logged instead of silently vanishing between poll iterations.
+func watchDeliveryReports(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-producer.Events():
+			msg, ok := e.(*kafka.Message)
+			if !ok {
+				continue
+			}
+			if msg.TopicPartition.Error != nil {
+				logger.Log.Error("Delivery failed",
+					zap.String("topic", *msg.TopicPartition.Topic),
+					zap.Error(msg.TopicPartition.Error))
+			}
+		}
+	}
+}
+
 func processMessage(msg *kafka.Message) {
 	var normalized events.NormalizedEvent
 	if err := protojson.Unmarshal(msg.Value, &normalized); err != nil {
 		logger.Log.Error("Failed to unmarshal event", zap.Error(err))
+		publishDeadLetter(msg, *msg.TopicPartition.Topic, "unmarshal", err)
+		return
+	}
+
+	if _, hit := seen.Touch(normalized.EventId, dedupe.Entry{EventID: normalized.EventId, FirstSeen: time.Now(), LastSeen: time.Now()}); hit {
+		logger.Log.Info("Dropping duplicate event", zap.String("event_id", normalized.EventId))
 		return
 	}
 
 	logger.Log.Info("Processing event", zap.String("event_id", normalized.EventId))
 
 	domainEvent := enrich.Enrich(&normalized)
-	route(domainEvent)
+	if domainEvent == nil {
+		publishDeadLetter(msg, *msg.TopicPartition.Topic, "enrich", fmt.Errorf("enrich returned no domain event for event_id=%s", normalized.EventId))
+		return
+	}
+
+	route(msg, domainEvent)
 }
 
-func route(ev *events.DomainEvent) {
+// route keys the produced message on IncidentId (falling back to EventId)
+// so every event for one incident lands on the same partition and is
+// processed in order, instead of being spread across partitions by the
+// default round-robin/random partitioner.
+func route(original *kafka.Message, ev *events.DomainEvent) {
 	topic := fmt.Sprintf("events.%s", ev.Domain)
 	val, err := protojson.Marshal(ev)
 	if err != nil {
 		logger.Log.Error("Failed to marshal domain event", zap.Error(err))
+		publishDeadLetter(original, *original.TopicPartition.Topic, "marshal", err)
 		return
 	}
 
+	key := ev.IncidentId
+	if key == "" {
+		key = ev.EventId
+	}
+
 	err = producer.Produce(&kafka.Message{
 		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            []byte(key),
 		Value:          val,
 	}, nil)
 
 	if err != nil {
 		logger.Log.Error("Failed to route event", zap.String("topic", topic), zap.Error(err))
+		publishDeadLetter(original, *original.TopicPartition.Topic, "produce", err)
 	} else {
 		logger.Log.Info("Routed event", zap.String("topic", topic), zap.String("event_id", ev.EventId))
 	}
 }
+
+// publishDeadLetter republishes the raw, unprocessable message onto
+// events.deadletter with headers identifying where it came from, why it
+// failed, and at which pipeline stage, so it can be inspected or replayed
+// without digging through router logs.
+func publishDeadLetter(original *kafka.Message, sourceTopic, stage string, cause error) {
+	if producer == nil {
+		return
+	}
+
+	err := producer.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &deadletterTopic, Partition: kafka.PartitionAny},
+		Value:          original.Value,
+		Headers: []kafka.Header{
+			{Key: "source-topic", Value: []byte(sourceTopic)},
+			{Key: "error", Value: []byte(cause.Error())},
+			{Key: "stage", Value: []byte(stage)},
+		},
+	}, nil)
+	if err != nil {
+		logger.Log.Error("Failed to publish to dead-letter topic", zap.String("stage", stage), zap.Error(err))
+	}
+}
@@ -3,49 +3,155 @@ package e2e
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/ChiragJS/IncidentResponseOrchestrator/tests/e2e/kafkaassert"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-// TestEndToEndFlow assumes the docker-compose environment is running.
-// It sends an alert to the Ingest service and waits (naively) to verify the flow.
-// In a real scenario, we would consume from the 'actions.status' Kafka topic to verify completion.
+// TestEndToEndFlow assumes the docker-compose environment is running. It
+// sends a table of AlertManager-shaped payloads exercising each
+// enrich.determineDomain branch to the Ingest service, then uses
+// kafkaassert to verify that each event is routed to the expected
+// events.<domain> topic within a deadline, logging how long routing took.
+// TestActionReachesTerminalStatus covers the rest of the pipeline up to a
+// terminal actions.status.
 func TestEndToEndFlow(t *testing.T) {
 	ingestURL := "http://localhost:8080/ingest"
+	broker := os.Getenv("KAFKA_BROKER")
+	if broker == "" {
+		broker = "localhost:9092"
+	}
+
+	harness, err := kafkaassert.New(broker, nil)
+	require.NoError(t, err, "Failed to start Kafka verification harness")
+	defer harness.Close()
+
+	cases := []struct {
+		name     string
+		payload  map[string]interface{}
+		domain   string // expected events.<domain> topic
+	}{
+		{
+			name:   "kubernetes source routes to k8s domain",
+			domain: "k8s",
+			payload: map[string]interface{}{
+				"source":      "kubernetes",
+				"severity":    "critical",
+				"description": "Pod CrashLoopBackOff",
+				"labels":      map[string]interface{}{"pod": "ingest-7c9d4f-abcde", "service": "ingest"},
+			},
+		},
+		{
+			name:   "deployment alert name routes to k8s domain",
+			domain: "k8s",
+			payload: map[string]interface{}{
+				"source":      "integration_test",
+				"severity":    "warning",
+				"alert":       "DeploymentReplicasMismatch",
+				"description": "Deployment replica mismatch",
+				"labels":      map[string]interface{}{"service": "router"},
+			},
+		},
+		{
+			name:   "kafka alert name routes to infra domain",
+			domain: "infra",
+			payload: map[string]interface{}{
+				"source":      "integration_test",
+				"severity":    "warning",
+				"alert":       "KafkaConsumerLagHigh",
+				"description": "Consumer group lag",
+				"labels":      map[string]interface{}{"service": "executor"},
+			},
+		},
+		{
+			name:   "postgres source routes to db domain",
+			domain: "db",
+			payload: map[string]interface{}{
+				"source":      "postgres",
+				"severity":    "critical",
+				"description": "Connection pool exhausted",
+				"labels":      map[string]interface{}{"service": "policy-engine"},
+			},
+		},
+		{
+			name:   "unrecognized alert falls back to infra domain",
+			domain: "infra",
+			payload: map[string]interface{}{
+				"source":      "integration_test",
+				"severity":    "info",
+				"description": "Unclassified alert",
+				"labels":      map[string]interface{}{"alertname": "UnclassifiedAlert", "service": "ingest"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			resp, err := http.Post(ingestURL, "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err, "Failed to send alert to Ingest service")
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusAccepted, resp.StatusCode, "Ingest service should accept the alert")
+
+			var respBody map[string]string
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			eventID := respBody["event_id"]
+			require.NotEmpty(t, eventID, "Ingest response should carry an event_id")
+
+			routed, err := harness.WaitForTopic(eventID, "events."+tc.domain, 15*time.Second)
+			require.NoError(t, err, "Event should be routed to the expected domain topic")
+			t.Logf("event %s routed to events.%s after %s", eventID, tc.domain, routed.Elapsed)
+		})
+	}
+}
+
+// TestActionReachesTerminalStatus sends a single alert designed to trigger
+// an auto-approved remediation action and waits for the executor to report
+// success via actions.status, proving the whole pipeline end to end rather
+// than just API ingestion.
+func TestActionReachesTerminalStatus(t *testing.T) {
+	ingestURL := "http://localhost:8080/ingest"
+	broker := os.Getenv("KAFKA_BROKER")
+	if broker == "" {
+		broker = "localhost:9092"
+	}
+
+	harness, err := kafkaassert.New(broker, nil)
+	require.NoError(t, err, "Failed to start Kafka verification harness")
+	defer harness.Close()
 
 	payload := map[string]interface{}{
-		"source":      "integration_test",
+		"source":      "kubernetes",
 		"severity":    "critical",
 		"description": "Integration test CPU spike",
-		// Mock data that the Router expects to trigger 'events.k8s'
-		"source_details": "k8s_cluster_metrics",
+		"labels":      map[string]interface{}{"alertname": "IntegrationTestCPUSpike", "pod": "ingest-7c9d4f-abcde", "service": "ingest", "namespace": "default"},
 	}
-	body, _ := json.Marshal(payload)
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
 
-	// 1. Send Alert
 	resp, err := http.Post(ingestURL, "application/json", bytes.NewBuffer(body))
-	if err != nil {
-		t.Fatalf("Failed to send alert to Ingest service: %v", err)
-	}
+	require.NoError(t, err, "Failed to send alert to Ingest service")
 	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
 
-	assert.Equal(t, http.StatusAccepted, resp.StatusCode, "Ingest service should accept the alert")
-
-	var responseMap map[string]string
-	json.NewDecoder(resp.Body).Decode(&responseMap)
-	eventID := responseMap["event_id"]
-	fmt.Printf("Alert accepted. Event ID: %s\n", eventID)
-
-	// 2. Wait for Processing (Naive wait, real test should consume Kafka)
-	fmt.Println("Waiting for async processing...")
-	time.Sleep(5 * time.Second)
+	var respBody map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	eventID := respBody["event_id"]
+	require.NotEmpty(t, eventID)
 
-	// Note: To fully verify, we'd need to create a temporary Kafka consumer here
-	// and listen to 'actions.status' or check logs.
-	// For this scaffold, we confirm API ingestion works.
-	fmt.Println("Test finished. Check docker logs for full trace.")
+	// actions.status is keyed by action_id, but the harness also indexes it
+	// under the event_id the policy engine stamped onto the action, so this
+	// only succeeds once the action derived from this event actually
+	// completes.
+	status, err := harness.WaitForActionStatus(eventID, "success", 30*time.Second)
+	require.NoError(t, err, "Pipeline should reach a terminal success status for event %s", eventID)
+	assert.Equal(t, "success", status.Status)
 }
@@ -0,0 +1,246 @@
+// Package kafkaassert gives e2e tests a way to verify the orchestrator's
+// Kafka pipeline end-to-end instead of sleeping and hoping: it consumes the
+// topics the pipeline writes to, correlates messages by the event_id that
+// threads through every stage (and by each stage's own ID as well), and
+// exposes deadline-based assertions.
+package kafkaassert
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChiragJS/IncidentResponseOrchestrator/pkg/events"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Topics is the default set of topics a full pipeline run touches.
+var Topics = []string{
+	"events.normalized",
+	"events.k8s",
+	"events.infra",
+	"events.db",
+	"decisions.k8s",
+	"decisions.infra",
+	"decisions.db",
+	"actions.approved",
+	"actions.status",
+}
+
+// StageLatency records when a correlated message was observed on a topic,
+// relative to the harness's start time, so tests can assert on per-stage
+// timing in addition to the terminal outcome.
+type StageLatency struct {
+	Topic   string
+	Elapsed time.Duration
+}
+
+// Harness consumes a fixed set of topics in the background and lets tests
+// wait for a specific event_id/action_id to show up on one of them.
+type Harness struct {
+	consumer *kafka.Consumer
+	start    time.Time
+
+	mu        sync.Mutex
+	byEventID map[string][]recordedMessage
+	done      chan struct{}
+}
+
+type recordedMessage struct {
+	topic    string
+	value    []byte
+	observed time.Time
+}
+
+// assignTimeout bounds how long New waits for the initial partition
+// assignment before giving up, so a broker that's down fails fast instead of
+// hanging forever.
+const assignTimeout = 15 * time.Second
+
+// New creates a Harness subscribed to topics (defaulting to Topics if nil)
+// and starts draining messages in the background. It blocks until the
+// consumer's partitions are assigned before returning, so a test's producer
+// can't write a message that lands before "latest" establishes its starting
+// offset and gets silently missed. Call Close when done.
+func New(broker string, topics []string) (*Harness, error) {
+	if len(topics) == 0 {
+		topics = Topics
+	}
+
+	consumer, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": broker,
+		"group.id":          fmt.Sprintf("e2e-kafkaassert-%d", time.Now().UnixNano()),
+		"auto.offset.reset": "latest",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create consumer: %w", err)
+	}
+
+	assigned := make(chan struct{})
+	var assignedOnce sync.Once
+	rebalanceCb := func(c *kafka.Consumer, event kafka.Event) error {
+		switch e := event.(type) {
+		case kafka.AssignedPartitions:
+			if err := c.Assign(e.Partitions); err != nil {
+				return err
+			}
+			assignedOnce.Do(func() { close(assigned) })
+		case kafka.RevokedPartitions:
+			return c.Unassign()
+		}
+		return nil
+	}
+
+	if err := consumer.SubscribeTopics(topics, rebalanceCb); err != nil {
+		consumer.Close()
+		return nil, fmt.Errorf("subscribe: %w", err)
+	}
+
+	h := &Harness{
+		consumer:  consumer,
+		start:     time.Now(),
+		byEventID: make(map[string][]recordedMessage),
+		done:      make(chan struct{}),
+	}
+	go h.drain()
+
+	// The rebalance callback only fires as Poll drives the consumer's event
+	// loop, which h.drain is now doing, so wait on it rather than blocking
+	// inline before starting the drain loop.
+	select {
+	case <-assigned:
+	case <-time.After(assignTimeout):
+		h.Close()
+		return nil, fmt.Errorf("timed out after %s waiting for partition assignment", assignTimeout)
+	}
+
+	return h, nil
+}
+
+// Close stops the background drain loop and the underlying consumer.
+func (h *Harness) Close() {
+	close(h.done)
+	h.consumer.Close()
+}
+
+func (h *Harness) drain() {
+	for {
+		select {
+		case <-h.done:
+			return
+		default:
+			ev := h.consumer.Poll(100)
+			if ev == nil {
+				continue
+			}
+			msg, ok := ev.(*kafka.Message)
+			if !ok {
+				continue
+			}
+			h.record(*msg.TopicPartition.Topic, msg.Value)
+		}
+	}
+}
+
+func (h *Harness) record(topic string, value []byte) {
+	keys := correlationKeys(topic, value)
+	if len(keys) == 0 {
+		return
+	}
+
+	msg := recordedMessage{topic: topic, value: value, observed: time.Now()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range keys {
+		h.byEventID[key] = append(h.byEventID[key], msg)
+	}
+}
+
+// correlationKeys extracts every ID a message can legitimately be looked up
+// by. Every message shape the pipeline emits carries the event_id of the
+// alert that started it, in addition to whatever ID is primary at that
+// stage (decision_id, action_id, ...), so a message is indexed under both:
+// tests that only know the originating event_id (e.g. from the Ingest
+// response) can still wait on a decision, action, or terminal status
+// derived from it.
+func correlationKeys(topic string, value []byte) []string {
+	var normalized events.NormalizedEvent
+	if protojson.Unmarshal(value, &normalized) == nil && normalized.EventId != "" {
+		return []string{normalized.EventId}
+	}
+	var domain events.DomainEvent
+	if protojson.Unmarshal(value, &domain) == nil && domain.EventId != "" {
+		return []string{domain.EventId}
+	}
+	var decision events.Decision
+	if protojson.Unmarshal(value, &decision) == nil && decision.DecisionId != "" {
+		return dedupeNonEmpty(decision.DecisionId, decision.EventId)
+	}
+	var action events.Action
+	if protojson.Unmarshal(value, &action) == nil && action.ActionId != "" {
+		return dedupeNonEmpty(action.ActionId, action.EventId)
+	}
+	var status events.ActionStatus
+	if protojson.Unmarshal(value, &status) == nil && status.ActionId != "" {
+		return dedupeNonEmpty(status.ActionId, status.EventId)
+	}
+	return nil
+}
+
+// dedupeNonEmpty returns the non-empty, de-duplicated subset of ids,
+// preserving order.
+func dedupeNonEmpty(ids ...string) []string {
+	out := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// WaitForActionStatus blocks until an ActionStatus with the given status is
+// observed on actions.status for actionID, or timeout elapses.
+func (h *Harness) WaitForActionStatus(actionID, status string, timeout time.Duration) (*events.ActionStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if msg, ok := h.find(actionID, "actions.status"); ok {
+			var as events.ActionStatus
+			if err := protojson.Unmarshal(msg.value, &as); err == nil && as.Status == status {
+				return &as, nil
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil, fmt.Errorf("timed out after %s waiting for action %s to reach status %q", timeout, actionID, status)
+}
+
+// WaitForTopic blocks until any message correlated with id is observed on
+// topic, returning how long after harness start it was seen.
+func (h *Harness) WaitForTopic(id, topic string, timeout time.Duration) (StageLatency, error) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if msg, ok := h.find(id, topic); ok {
+			return StageLatency{Topic: topic, Elapsed: msg.observed.Sub(h.start)}, nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return StageLatency{}, fmt.Errorf("timed out after %s waiting for %s on topic %s", timeout, id, topic)
+}
+
+func (h *Harness) find(id, topic string) (recordedMessage, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, msg := range h.byEventID[id] {
+		if msg.topic == topic {
+			return msg, true
+		}
+	}
+	return recordedMessage{}, false
+}